@@ -0,0 +1,215 @@
+package pixelmunk
+
+import (
+	"cmp"
+	"fmt"
+	"github.com/vova616/chipmunk"
+	"github.com/vova616/chipmunk/vect"
+	"log"
+	"math"
+	"reflect"
+	"slices"
+	"time"
+)
+
+// chipmunk finds the colliding shapes with a tree that it updates by walking a map, so the order in which it finds
+// them, and which shape of a pair comes first, changes from run to run. Both change the result of a step: the
+// solver handles the collisions in the order they were found, and the contact points of two boxes depend on which
+// box is first. sweepIndex replaces chipmunk's index of the dynamic shapes with a sort and sweep that reports the
+// pairs in the order of their Objects in the World, which makes the simulation deterministic: a replay or a restored
+// Snapshot follows the same path as the original. A sweepIndex also replaces chipmunk's tree of static shapes, which
+// chipmunk never updates when a static body is moved.
+type sweepIndex struct {
+	world  *World
+	space  *chipmunk.Space
+	shapes []*chipmunk.Shape
+	stamp  time.Duration
+	// static is the index of the static shapes, which the index of the dynamic shapes sweeps together with its own
+	static *sweepIndex
+}
+
+var _ chipmunk.SpatialIndexClass = &sweepIndex{}
+
+// shapeKey identifies a shape by the position of its body's Object in the World, which is the same in every run
+// and restored by Restore. Shapes of bodies that aren't part of the World come after all others, in order of
+// creation.
+type shapeKey struct {
+	object int
+	shape  int
+}
+
+func compareShapeKeys(a, b shapeKey) int {
+	if c := cmp.Compare(a.object, b.object); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.shape, b.shape)
+}
+
+// useSweepIndex makes sure that the World's Space indexes its dynamic and static shapes with a sweepIndex. If the
+// indexes of the Space can't be found, chipmunk's own indexes are kept and the simulation isn't deterministic.
+func (w *World) useSweepIndex() {
+	if w.index != nil && w.index.space == w.Space {
+		return
+	}
+	w.index = &sweepIndex{world: w, space: w.Space}
+	active, static, err := spaceIndexes(w.Space)
+	if err != nil {
+		log.Printf("pixelmunk: %v: using chipmunk's index, so the simulation isn't deterministic", err)
+		return
+	}
+	w.index.static = &sweepIndex{world: w, space: w.Space}
+	w.index.replace(active, w.Space.Query)
+	w.index.static.replace(static, w.Space.QueryStatic)
+}
+
+// spaceIndexes returns the indexes of the dynamic and static shapes of the Space. chipmunk doesn't export them, so
+// they're looked up with reflection.
+func spaceIndexes(space *chipmunk.Space) (active, static *chipmunk.SpatialIndex, err error) {
+	if active, err = spaceIndex(space, "activeShapes"); err != nil {
+		return nil, nil, err
+	}
+	if static, err = spaceIndex(space, "staticShapes"); err != nil {
+		return nil, nil, err
+	}
+	return active, static, nil
+}
+
+func spaceIndex(space *chipmunk.Space, name string) (*chipmunk.SpatialIndex, error) {
+	field := reflect.ValueOf(space).Elem().FieldByName(name)
+	if !field.IsValid() || field.Type() != reflect.TypeFor[*chipmunk.SpatialIndex]() {
+		return nil, fmt.Errorf("chipmunk.Space has no field %s of type *chipmunk.SpatialIndex", name)
+	}
+	index := (*chipmunk.SpatialIndex)(field.UnsafePointer())
+	if index == nil {
+		return nil, fmt.Errorf("chipmunk.Space has no %s index", name)
+	}
+	return index, nil
+}
+
+// replace makes the sweepIndex the index, moving the shapes of the index found by query into it
+func (s *sweepIndex) replace(index *chipmunk.SpatialIndex, query func(chipmunk.Indexable, chipmunk.AABB, chipmunk.SpatialIndexQueryFunc)) {
+	inf := vect.Float(math.Inf(1))
+	everything := chipmunk.NewAABB(-inf, -inf, inf, inf)
+	query(nil, everything, func(_, shape chipmunk.Indexable) {
+		s.shapes = append(s.shapes, shape.Shape())
+	})
+	index.SpatialIndexClass = s
+}
+
+// shapeKeys returns the key of every shape of the World's Objects. The keys are kept until the Objects change (see
+// Add, Remove and Restore).
+func (w *World) shapeKeys() func(*chipmunk.Shape) shapeKey {
+	if w.shapeKeyCache == nil {
+		w.shapeKeyCache = make(map[*chipmunk.Shape]shapeKey)
+		for i, object := range w.Objects {
+			if object.GetType() != DrawableBody {
+				continue
+			}
+			for j, shape := range object.GetBody().Shapes {
+				w.shapeKeyCache[shape] = shapeKey{object: i, shape: j}
+			}
+		}
+	}
+	keys := w.shapeKeyCache
+	return func(shape *chipmunk.Shape) shapeKey {
+		if k, ok := keys[shape]; ok {
+			return k
+		}
+		return shapeKey{object: len(w.Objects), shape: int(shape.Hash())}
+	}
+}
+
+func (s *sweepIndex) Destroy() {
+	s.shapes = nil
+}
+
+func (s *sweepIndex) Count() int {
+	return len(s.shapes)
+}
+
+// Each isn't supported: chipmunk's nodes can't be created outside chipmunk. chipmunk only calls it from its own tree.
+func (s *sweepIndex) Each(chipmunk.HashSetIterator) {}
+
+// Contains reports whether the shape is in the index
+func (s *sweepIndex) Contains(obj chipmunk.Indexable) bool {
+	return slices.Contains(s.shapes, obj.Shape())
+}
+
+func (s *sweepIndex) Insert(obj chipmunk.Indexable) {
+	s.shapes = append(s.shapes, obj.Shape())
+	s.stamp++
+}
+
+func (s *sweepIndex) Remove(obj chipmunk.Indexable) {
+	if i := slices.Index(s.shapes, obj.Shape()); i >= 0 {
+		s.shapes = slices.Delete(s.shapes, i, i+1)
+	}
+}
+
+// Reindex and ReindexObject have nothing to do: ReindexQuery reads the current bounding boxes of the shapes
+func (s *sweepIndex) Reindex()                                                                  {}
+func (s *sweepIndex) ReindexObject(chipmunk.Indexable)                                          {}
+func (s *sweepIndex) Stamp() time.Duration                                                      { return s.stamp }
+func (s *sweepIndex) SegmentQuery(chipmunk.Indexable, vect.Vect, vect.Vect, vect.Float, func()) {}
+
+// Query calls fnc for every dynamic shape whose bounding box overlaps aabb
+func (s *sweepIndex) Query(obj chipmunk.Indexable, aabb chipmunk.AABB, fnc chipmunk.SpatialIndexQueryFunc) {
+	for _, shape := range s.shapes {
+		if chipmunk.TestOverlap(shape.BB, aabb) {
+			fnc(obj, shape)
+		}
+	}
+}
+
+// ReindexQuery calls fnc for every pair of overlapping shapes, of which at least one is dynamic, ordered by the
+// keys of the shapes. The shape with the lowest key comes first.
+func (s *sweepIndex) ReindexQuery(fnc chipmunk.SpatialIndexQueryFunc) {
+	key := s.world.shapeKeys()
+	type pair struct {
+		a, b       *chipmunk.Shape
+		keyA, keyB shapeKey
+	}
+	var pairs []pair
+	add := func(a, b *chipmunk.Shape) {
+		ka, kb := key(a), key(b)
+		if compareShapeKeys(ka, kb) > 0 {
+			a, b, ka, kb = b, a, kb, ka
+		}
+		pairs = append(pairs, pair{a: a, b: b, keyA: ka, keyB: kb})
+	}
+
+	// the static shapes are swept together with the dynamic ones, but never paired with each other
+	type entry struct {
+		shape  *chipmunk.Shape
+		static bool
+	}
+	sorted := make([]entry, 0, len(s.shapes)+len(s.static.shapes))
+	for _, shape := range s.shapes {
+		sorted = append(sorted, entry{shape: shape})
+	}
+	for _, shape := range s.static.shapes {
+		sorted = append(sorted, entry{shape: shape, static: true})
+	}
+	slices.SortFunc(sorted, func(a, b entry) int { return cmp.Compare(a.shape.BB.Lower.X, b.shape.BB.Lower.X) })
+	for i, a := range sorted {
+		for _, b := range sorted[i+1:] {
+			if b.shape.BB.Lower.X > a.shape.BB.Upper.X {
+				break
+			}
+			if !(a.static && b.static) && chipmunk.TestOverlap(a.shape.BB, b.shape.BB) {
+				add(a.shape, b.shape)
+			}
+		}
+	}
+
+	slices.SortFunc(pairs, func(p, q pair) int {
+		if c := compareShapeKeys(p.keyA, q.keyA); c != 0 {
+			return c
+		}
+		return compareShapeKeys(p.keyB, q.keyB)
+	})
+	for _, p := range pairs {
+		fnc(p.a, p.b)
+	}
+	s.stamp++
+}
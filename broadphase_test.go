@@ -0,0 +1,44 @@
+package pixelmunk
+
+import (
+	"github.com/vova616/chipmunk"
+	"github.com/vova616/chipmunk/vect"
+	"testing"
+)
+
+func TestWorld_useSweepIndex(t *testing.T) {
+	// the indexes are private fields of chipmunk.Space: this fails if a new version of chipmunk renames them
+	w := NewWorld("index", 0, 0, 100, 100)
+	w.useSweepIndex()
+	active, static, err := spaceIndexes(w.Space)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, index := range map[string]*chipmunk.SpatialIndex{"active": active, "static": static} {
+		if _, ok := index.SpatialIndexClass.(*sweepIndex); !ok {
+			t.Errorf("%s shapes: got %T, want a sweepIndex", name, index.SpatialIndexClass)
+		}
+	}
+}
+
+func TestWorld_shapeKeys(t *testing.T) {
+	w := NewWorld("keys", 0, 0, 100, 100)
+	circles := make([]*Object, 3)
+	for i := range circles {
+		circles[i] = NewCircle(DrawableOptions{BodyOptions: BodyOptions{
+			Position:      vect.Vect{X: vect.Float(20 * i)},
+			Mass:          1,
+			CircleOptions: CircleOptions{Radius: 5},
+		}})
+		w.Add(circles[i])
+	}
+	shape := circles[2].GetBody().Shapes[0]
+	if got, want := w.shapeKeys()(shape), (shapeKey{object: 2}); got != want {
+		t.Errorf("key: got %v, want %v", got, want)
+	}
+	// removing an Object moves the ones after it
+	w.Remove(circles[0])
+	if got, want := w.shapeKeys()(shape), (shapeKey{object: 1}); got != want {
+		t.Errorf("key after Remove: got %v, want %v", got, want)
+	}
+}
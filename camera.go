@@ -0,0 +1,105 @@
+package pixelmunk
+
+import (
+	"github.com/gopxl/pixel/v2"
+	"github.com/vova616/chipmunk/vect"
+	"math"
+	"time"
+)
+
+// Camera determines which part of the World is shown in the window. Without a Camera, the window shows the World's
+// Bounds, in world coordinates.
+type Camera struct {
+	// Position is the point in the World that is shown at the centre of the window
+	Position vect.Vect
+	// Zoom scales the World: more than 1 zooms in, less than 1 zooms out. Defaults to 1.
+	Zoom float64
+	// Rotation turns the camera counterclockwise, in radians
+	Rotation float64
+	// Follow moves the camera along with the Object. Only supported by the default run loop.
+	Follow *Object
+	// FollowSpeed determines how quickly the camera catches up with the Object it follows: every second, the
+	// distance to the Object shrinks by a factor e^FollowSpeed. Zero keeps the Object at the centre of the window.
+	FollowSpeed float64
+	// Limits keeps the camera's view inside a rectangle, in world coordinates. If the view is larger than Limits,
+	// the camera is centred on Limits. A zero rectangle means no limits.
+	Limits pixel.Rect
+}
+
+func (c *Camera) zoom() float64 {
+	if c.Zoom <= 0 {
+		return 1
+	}
+	return c.Zoom
+}
+
+// matrix returns the matrix that projects world coordinates onto the screen
+func (c *Camera) matrix(screen pixel.Rect) pixel.Matrix {
+	return pixel.IM.
+		Moved(toPixel(c.Position).Scaled(-1)).
+		Rotated(pixel.ZV, -c.Rotation).
+		Scaled(pixel.ZV, c.zoom()).
+		Moved(screen.Center())
+}
+
+// update moves the camera towards the position of the Object it follows and keeps its view inside the Limits
+func (c *Camera) update(target vect.Vect, elapsed time.Duration, screen pixel.Rect) {
+	if c.Follow != nil {
+		if c.FollowSpeed <= 0 {
+			c.Position = target
+		} else {
+			f := vect.Float(1 - math.Exp(-c.FollowSpeed*elapsed.Seconds()))
+			c.Position = vect.Add(c.Position, vect.Mult(vect.Sub(target, c.Position), f))
+		}
+	}
+	if c.Limits == (pixel.Rect{}) {
+		return
+	}
+
+	// half the size of the (rotated) view, in world coordinates
+	sin, cos := math.Abs(math.Sin(c.Rotation)), math.Abs(math.Cos(c.Rotation))
+	halfW := (cos*screen.W() + sin*screen.H()) / 2 / c.zoom()
+	halfH := (sin*screen.W() + cos*screen.H()) / 2 / c.zoom()
+	c.Position = vect.Vect{
+		X: vect.Float(clampView(float64(c.Position.X), halfW, c.Limits.Min.X, c.Limits.Max.X)),
+		Y: vect.Float(clampView(float64(c.Position.Y), halfH, c.Limits.Min.Y, c.Limits.Max.Y)),
+	}
+}
+
+// clampView clamps the centre of a view of half size half, so the view stays between low and high
+func clampView(centre, half, low, high float64) float64 {
+	if high-low < 2*half {
+		return (low + high) / 2
+	}
+	return math.Min(math.Max(centre, low+half), high-half)
+}
+
+// updateCamera updates the World's Camera, if it has one, following the interpolated position of its Object
+func (w *World) updateCamera(elapsed time.Duration, alpha float64) {
+	if w.Camera == nil {
+		return
+	}
+	var target vect.Vect
+	if w.Camera.Follow != nil {
+		target = w.interpolatedState(w.Camera.Follow.GetBody(), alpha).position
+	}
+	w.Camera.update(target, elapsed, w.Bounds)
+}
+
+// viewMatrix returns the matrix that projects world coordinates onto the screen
+func (w *World) viewMatrix() pixel.Matrix {
+	if w.Camera == nil {
+		return pixel.IM
+	}
+	return w.Camera.matrix(w.Bounds)
+}
+
+// WorldToScreen converts a point in world coordinates into window coordinates
+func (w *World) WorldToScreen(p vect.Vect) pixel.Vec {
+	return w.viewMatrix().Chained(w.screenMatrix()).Project(toPixel(p))
+}
+
+// ScreenToWorld converts a point in window coordinates, e.g. the position of the mouse, into world coordinates
+func (w *World) ScreenToWorld(p pixel.Vec) vect.Vect {
+	return toVect(w.viewMatrix().Chained(w.screenMatrix()).Unproject(p))
+}
@@ -0,0 +1,329 @@
+package pixelmunk
+
+import (
+	"cmp"
+	"github.com/vova616/chipmunk"
+	"github.com/vova616/chipmunk/vect"
+	"math"
+	"reflect"
+	"slices"
+)
+
+// CollisionType identifies a kind of Object (e.g. ball, wall, player) in collision callbacks. Zero means no type.
+type CollisionType int
+
+// Collision describes a collision between two Objects
+type Collision struct {
+	A, B *Object
+	// Contacts are the contact points, in world coordinates. Contacts is empty when the Objects separate.
+	Contacts []vect.Vect
+	// Normal is the collision normal, pointing from A to B
+	Normal vect.Vect
+	// Impulse is the impulse applied to B by the contacts of this collision during the step. Only set in PostSolve
+	// callbacks.
+	Impulse vect.Vect
+}
+
+// CollisionFilter selects the collisions that a callback receives. A zero CollisionFilter matches all collisions.
+//
+// If Object is set, only collisions involving that Object match. If Type and/or OtherType are set, only collisions
+// between an Object of Type and an Object of OtherType match. The Collision passed to the callback is ordered
+// to match the filter: A is the Object (or the Object of Type) and B is the other Object.
+type CollisionFilter struct {
+	Object    *Object
+	Type      CollisionType
+	OtherType CollisionType
+}
+
+func (f CollisionFilter) matches(a, b *Object) bool {
+	return (f.Object == nil || f.Object == a) &&
+		(f.Type == 0 || f.Type == a.options.BodyOptions.CollisionType) &&
+		(f.OtherType == 0 || f.OtherType == b.options.BodyOptions.CollisionType)
+}
+
+type collisionCallback[T any] struct {
+	filter   CollisionFilter
+	callback T
+}
+
+type collisionCallbacks struct {
+	begin     []collisionCallback[func(Collision) bool]
+	preSolve  []collisionCallback[func(Collision) bool]
+	postSolve []collisionCallback[func(Collision)]
+	separate  []collisionCallback[func(Collision)]
+}
+
+// OnCollisionBegin registers a callback that is called when two Objects first touch. If the callback returns false,
+// the collision is ignored until the Objects separate.
+func (w *World) OnCollisionBegin(filter CollisionFilter, callback func(Collision) bool) {
+	w.collisionCallbacks.begin = append(w.collisionCallbacks.begin, collisionCallback[func(Collision) bool]{filter, callback})
+}
+
+// OnCollisionPreSolve registers a callback that is called during every step in which two Objects touch, before
+// the collision is resolved. If the callback returns false, the collision is ignored for this step.
+func (w *World) OnCollisionPreSolve(filter CollisionFilter, callback func(Collision) bool) {
+	w.collisionCallbacks.preSolve = append(w.collisionCallbacks.preSolve, collisionCallback[func(Collision) bool]{filter, callback})
+}
+
+// OnCollisionPostSolve registers a callback that is called during every step in which two Objects touch, after
+// the collision is resolved. Collision.Impulse is only set for PostSolve callbacks.
+func (w *World) OnCollisionPostSolve(filter CollisionFilter, callback func(Collision)) {
+	w.collisionCallbacks.postSolve = append(w.collisionCallbacks.postSolve, collisionCallback[func(Collision)]{filter, callback})
+}
+
+// OnCollisionSeparate registers a callback that is called when two Objects stop touching, or when one of them
+// is removed from the World.
+func (w *World) OnCollisionSeparate(filter CollisionFilter, callback func(Collision)) {
+	w.collisionCallbacks.separate = append(w.collisionCallbacks.separate, collisionCallback[func(Collision)]{filter, callback})
+}
+
+// collisionState tracks an ongoing collision between two shapes
+type collisionState struct {
+	shapeA, shapeB *chipmunk.Shape
+	a, b           *Object
+	ignored        bool
+}
+
+// bodyCollisionHandler is the chipmunk.CollisionCallback that the World installs on the bodies of its Objects.
+// It wraps the handler that the application may have set on the body.
+type bodyCollisionHandler struct {
+	world *World
+	body  *chipmunk.Body
+	next  chipmunk.CollisionCallback
+}
+
+var _ chipmunk.CollisionCallback = &bodyCollisionHandler{}
+
+// primary reports whether the handler should process the arbiter: chipmunk calls the handlers of both bodies,
+// but each collision should only be reported once. The handler of BodyB only processes it if BodyA wasn't
+// added to the World.
+func (h *bodyCollisionHandler) primary(arb *chipmunk.Arbiter) bool {
+	if h.body == arb.BodyA {
+		return true
+	}
+	_, ok := arb.BodyA.CallbackHandler.(*bodyCollisionHandler)
+	return !ok
+}
+
+// CollisionEnter accepts all collisions, unless the application's handler ignores them. Begin callbacks are called
+// from CollisionPreSolve instead: chipmunk still resolves collisions that were ignored in CollisionEnter.
+func (h *bodyCollisionHandler) CollisionEnter(arb *chipmunk.Arbiter) bool {
+	return h.next == nil || h.next.CollisionEnter(arb)
+}
+
+// CollisionPreSolve processes the collision. chipmunk only ignores the collision if all handlers return false
+// and doesn't call the second handler if the first one returns true, so the handler of the second body returns
+// false unless the application's handler accepts the collision.
+func (h *bodyCollisionHandler) CollisionPreSolve(arb *chipmunk.Arbiter) bool {
+	accept := h.next == nil || h.next.CollisionPreSolve(arb)
+	if !h.primary(arb) {
+		return h.next != nil && accept
+	}
+	return h.world.preSolve(arb) && accept
+}
+
+func (h *bodyCollisionHandler) CollisionPostSolve(arb *chipmunk.Arbiter) {
+	if h.next != nil {
+		h.next.CollisionPostSolve(arb)
+	}
+	if h.primary(arb) {
+		h.world.postSolve(arb)
+	}
+}
+
+func (h *bodyCollisionHandler) CollisionExit(arb *chipmunk.Arbiter) {
+	if h.next != nil {
+		h.next.CollisionExit(arb)
+	}
+	if h.primary(arb) {
+		h.world.separate(arb)
+	}
+}
+
+func (w *World) preSolve(arb *chipmunk.Arbiter) bool {
+	if w.removedBodies[arb.BodyA] || w.removedBodies[arb.BodyB] {
+		return false
+	}
+	a, b := w.bodyObjects[arb.BodyA], w.bodyObjects[arb.BodyB]
+	if a == nil || b == nil {
+		return true
+	}
+
+	state := w.collisions[arb]
+	// chipmunk reuses arbiters, so check that this is still the same collision
+	if state != nil && (state.shapeA != arb.ShapeA || state.shapeB != arb.ShapeB) {
+		delete(w.collisions, arb)
+		state = nil
+	}
+
+	// chipmunk only knows groups and layers: categories and masks are checked here. The masks may have changed
+	// since the collision began.
+	if !a.collidesWith(b) {
+		if state != nil {
+			w.endCollision(arb, state)
+		}
+		return false
+	}
+
+	if state == nil {
+		state = &collisionState{shapeA: arb.ShapeA, shapeB: arb.ShapeB, a: a, b: b}
+		if w.collisions == nil {
+			w.collisions = make(map[*chipmunk.Arbiter]*collisionState)
+		}
+		w.collisions[arb] = state
+		state.ignored = !callAll(w.collisionCallbacks.begin, newCollision(state, arb))
+	}
+	if state.ignored {
+		return false
+	}
+	return callAll(w.collisionCallbacks.preSolve, newCollision(state, arb))
+}
+
+func (w *World) postSolve(arb *chipmunk.Arbiter) {
+	state, ok := w.collisions[arb]
+	if !ok || state.ignored || len(w.collisionCallbacks.postSolve) == 0 {
+		return
+	}
+	c := newCollision(state, arb)
+	c.Impulse = totalImpulse(arb)
+	for _, cb := range w.collisionCallbacks.postSolve {
+		if c, ok := cb.filter.match(c); ok {
+			cb.callback(c)
+		}
+	}
+}
+
+func (w *World) separate(arb *chipmunk.Arbiter) {
+	state, ok := w.collisions[arb]
+	if !ok || state.shapeA != arb.ShapeA || state.shapeB != arb.ShapeB {
+		return
+	}
+	w.endCollision(arb, state)
+}
+
+// separateAll ends all collisions of the body, when it is removed from the World. The collisions end in the order of
+// the other Objects in the World, so that the separate callbacks run in the same order every time.
+func (w *World) separateAll(body *chipmunk.Body) {
+	type separation struct {
+		arb           *chipmunk.Arbiter
+		state         *collisionState
+		object, shape int
+	}
+	var separations []separation
+	for arb, state := range w.collisions {
+		other, otherShape := state.b, state.shapeB
+		if state.shapeB.Body == body {
+			other, otherShape = state.a, state.shapeA
+		} else if state.shapeA.Body != body {
+			continue
+		}
+		separations = append(separations, separation{
+			arb:    arb,
+			state:  state,
+			object: slices.Index(w.Objects, Drawable(other)),
+			shape:  slices.Index(otherShape.Body.Shapes, otherShape),
+		})
+	}
+	slices.SortFunc(separations, func(a, b separation) int {
+		return cmp.Or(cmp.Compare(a.object, b.object), cmp.Compare(a.shape, b.shape))
+	})
+	for _, s := range separations {
+		w.endCollision(s.arb, s.state)
+	}
+}
+
+func (w *World) endCollision(arb *chipmunk.Arbiter, state *collisionState) {
+	delete(w.collisions, arb)
+	c := Collision{A: state.a, B: state.b}
+	for _, cb := range w.collisionCallbacks.separate {
+		if c, ok := cb.filter.match(c); ok {
+			cb.callback(c)
+		}
+	}
+}
+
+// newCollision creates the Collision for an arbiter
+func newCollision(state *collisionState, arb *chipmunk.Arbiter) Collision {
+	c := Collision{A: state.a, B: state.b}
+	for _, contact := range arb.Contacts[:arb.NumContacts] {
+		c.Contacts = append(c.Contacts, contact.Position())
+	}
+	if arb.NumContacts > 0 {
+		c.Normal = arb.Contacts[0].Normal()
+	}
+	return c
+}
+
+// match reports whether the filter matches the collision, and returns the collision ordered to match the filter
+func (f CollisionFilter) match(c Collision) (Collision, bool) {
+	if f.matches(c.A, c.B) {
+		return c, true
+	}
+	if f.matches(c.B, c.A) {
+		return Collision{
+			A:        c.B,
+			B:        c.A,
+			Contacts: c.Contacts,
+			Normal:   vect.Mult(c.Normal, -1),
+			Impulse:  vect.Mult(c.Impulse, -1),
+		}, true
+	}
+	return c, false
+}
+
+// callAll calls all matching callbacks and reports whether all of them accepted the collision
+func callAll(callbacks []collisionCallback[func(Collision) bool], c Collision) bool {
+	accept := true
+	for _, cb := range callbacks {
+		if c, ok := cb.filter.match(c); ok {
+			accept = cb.callback(c) && accept
+		}
+	}
+	return accept
+}
+
+// totalImpulse returns the impulse that the contacts of the arbiter applied to its second body during the step.
+// chipmunk doesn't export the impulses that it accumulates in a contact, so these are read like the force of a body
+// (see bodyForce).
+func totalImpulse(arb *chipmunk.Arbiter) vect.Vect {
+	var impulse vect.Vect
+	for _, contact := range arb.Contacts[:arb.NumContacts] {
+		c := reflect.ValueOf(contact).Elem()
+		jn, jt := vect.Float(c.FieldByName("jnAcc").Float()), vect.Float(c.FieldByName("jtAcc").Float())
+		n := contact.Normal()
+		impulse = vect.Add(impulse, vect.Add(vect.Mult(n, jn), vect.Mult(vect.Perp(n), jt)))
+	}
+	return impulse
+}
+
+func isInfinite(v vect.Float) bool {
+	return math.IsInf(float64(v), 0)
+}
+
+// Occupants returns the Objects that currently overlap one of the sensor shapes of the sensor Object,
+// in the order in which they were added to the World
+func (w *World) Occupants(sensor *Object) []*Object {
+	inside := make(map[*Object]bool)
+	for _, state := range w.collisions {
+		if state.ignored {
+			continue
+		}
+		if state.a == sensor && state.shapeA.IsSensor {
+			inside[state.b] = true
+		}
+		if state.b == sensor && state.shapeB.IsSensor {
+			inside[state.a] = true
+		}
+	}
+
+	var occupants []*Object
+	for _, object := range w.Objects {
+		if object.GetType() != DrawableBody {
+			continue
+		}
+		if o := w.bodyObjects[object.GetBody()]; o != nil && inside[o] {
+			occupants = append(occupants, o)
+		}
+	}
+	return occupants
+}
@@ -0,0 +1,173 @@
+package pixelmunk
+
+import (
+	"context"
+	"github.com/vova616/chipmunk"
+	"github.com/vova616/chipmunk/vect"
+	"testing"
+)
+
+// foreignHandler is a chipmunk.CollisionCallback installed by the application instead of the World
+type foreignHandler struct {
+	enter int
+}
+
+func (h *foreignHandler) CollisionEnter(*chipmunk.Arbiter) bool {
+	h.enter++
+	return true
+}
+func (h *foreignHandler) CollisionPreSolve(*chipmunk.Arbiter) bool { return true }
+func (h *foreignHandler) CollisionPostSolve(*chipmunk.Arbiter)     {}
+func (h *foreignHandler) CollisionExit(*chipmunk.Arbiter)          {}
+
+func TestWorld_OnCollisionBegin(t *testing.T) {
+	tests := []struct {
+		name           string
+		foreignFloor   bool
+		foreignFalling bool
+	}{
+		{name: "world handlers"},
+		{name: "foreign handler on the floor", foreignFloor: true},
+		{name: "foreign handler on the falling body", foreignFalling: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := NewWorld("test", 0, 0, 100, 100)
+			w.Space.Gravity = vect.Vect{Y: -900}
+			floor := NewBox(DrawableOptions{BodyOptions: BodyOptions{
+				StaticBody: true,
+				Position:   vect.Vect{X: 50, Y: 5},
+				BoxOptions: BoxOptions{Width: 100, Height: 10},
+			}})
+			ball := NewCircle(DrawableOptions{BodyOptions: BodyOptions{
+				Position:      vect.Vect{X: 50, Y: 30},
+				Mass:          1,
+				CircleOptions: CircleOptions{Radius: 5},
+			}})
+			floor.GetBody().UserData = "floor"
+			ball.GetBody().UserData = "ball"
+			handler := &foreignHandler{}
+			if tt.foreignFloor {
+				floor.GetBody().CallbackHandler = handler
+			}
+			if tt.foreignFalling {
+				ball.GetBody().CallbackHandler = handler
+			}
+			w.Add(floor, ball)
+
+			var collisions []Collision
+			w.OnCollisionBegin(CollisionFilter{Object: ball}, func(c Collision) bool {
+				collisions = append(collisions, c)
+				return true
+			})
+			if err := w.RunHeadless(context.Background(), 60); err != nil {
+				t.Fatal(err)
+			}
+
+			if len(collisions) != 1 {
+				t.Fatalf("collisions: got %d, want 1", len(collisions))
+			}
+			if collisions[0].A != ball || collisions[0].B != floor {
+				t.Errorf("collision: got %v and %v, want the ball and the floor", collisions[0].A, collisions[0].B)
+			}
+			if (tt.foreignFloor || tt.foreignFalling) && handler.enter != 1 {
+				t.Errorf("foreign handler: got %d calls, want 1", handler.enter)
+			}
+			if floor.GetBody().UserData != "floor" || ball.GetBody().UserData != "ball" {
+				t.Errorf("UserData: got %v and %v", floor.GetBody().UserData, ball.GetBody().UserData)
+			}
+		})
+	}
+}
+
+func TestWorld_OnCollisionPostSolve_impulse(t *testing.T) {
+	// a box pushed into the corner between the floor and a wall, without friction
+	w := NewWorld("impulse", 0, 0, 100, 100)
+	w.Space.Gravity = vect.Vect{X: -300, Y: -900}
+	floor := NewBox(DrawableOptions{BodyOptions: BodyOptions{
+		StaticBody: true,
+		Position:   vect.Vect{X: 50, Y: 5},
+		BoxOptions: BoxOptions{Width: 100, Height: 10},
+	}})
+	wall := NewBox(DrawableOptions{BodyOptions: BodyOptions{
+		StaticBody: true,
+		Position:   vect.Vect{X: 5, Y: 50},
+		BoxOptions: BoxOptions{Width: 10, Height: 80},
+	}})
+	box := NewBox(DrawableOptions{BodyOptions: BodyOptions{
+		Position:   vect.Vect{X: 20, Y: 20},
+		Mass:       2,
+		BoxOptions: BoxOptions{Width: 10, Height: 10},
+	}})
+	w.Add(floor, wall, box)
+	for range 120 {
+		w.Step(w.physicsDt())
+	}
+
+	impulses := make(map[*Object]vect.Vect)
+	w.OnCollisionPostSolve(CollisionFilter{Object: box}, func(c Collision) {
+		impulses[c.B] = c.Impulse
+	})
+	dt := w.physicsDt()
+	w.Step(dt)
+
+	// each collision only carries its own share of the weight of the box
+	tests := []struct {
+		name   string
+		object *Object
+		want   vect.Vect
+	}{
+		{name: "floor", object: floor, want: vect.Vect{Y: -2 * 900 * dt}},
+		{name: "wall", object: wall, want: vect.Vect{X: -2 * 300 * dt}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := impulses[tt.object]
+			if !ok {
+				t.Fatal("no collision")
+			}
+			if vect.Length(vect.Sub(got, tt.want)) > 0.05*vect.Length(tt.want) {
+				t.Errorf("impulse: got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWorld_Remove_separateOrder(t *testing.T) {
+	w := NewWorld("separate", 0, 0, 100, 100)
+	w.Space.Gravity = vect.Vect{Y: -900}
+	floor := NewBox(DrawableOptions{BodyOptions: BodyOptions{
+		StaticBody: true,
+		Position:   vect.Vect{X: 50, Y: 5},
+		BoxOptions: BoxOptions{Width: 100, Height: 10},
+	}})
+	w.Add(floor)
+	var balls []*Object
+	for i := range 6 {
+		ball := NewCircle(DrawableOptions{BodyOptions: BodyOptions{
+			Position:      vect.Vect{X: vect.Float(10 + 15*i), Y: 15},
+			Mass:          1,
+			CircleOptions: CircleOptions{Radius: 5},
+		}})
+		balls = append(balls, ball)
+		w.Add(ball)
+	}
+	for range 30 {
+		w.Step(w.physicsDt())
+	}
+
+	var separated []*Object
+	w.OnCollisionSeparate(CollisionFilter{Object: floor}, func(c Collision) {
+		separated = append(separated, c.B)
+	})
+	w.Remove(floor)
+
+	if len(separated) != len(balls) {
+		t.Fatalf("separated: got %d collisions, want %d", len(separated), len(balls))
+	}
+	for i, ball := range balls {
+		if separated[i] != ball {
+			t.Errorf("collision %d: got %v, want ball %d", i, separated[i], i)
+		}
+	}
+}
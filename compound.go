@@ -0,0 +1,163 @@
+package pixelmunk
+
+import (
+	"errors"
+	"fmt"
+	"github.com/vova616/chipmunk"
+	"github.com/vova616/chipmunk/vect"
+	"image/color"
+)
+
+// balanced is how far the centre of mass of a compound Object may be from its Position before its shapes are moved
+const balanced = 1e-3
+
+// ShapeSpec describes one of the shapes of a compound Object
+type ShapeSpec struct {
+	Type chipmunk.ShapeType
+	// Offset of the shape, relative to the Object's Position
+	Offset     vect.Vect
+	Mass       vect.Float
+	Elasticity vect.Float
+	Friction   vect.Float
+	// Color of the shape. If nil, the Color in the Object's DrawableOptions is used
+	Color color.Color
+	// Sensor shapes report collisions, but don't push other shapes
+	Sensor         bool
+	CircleOptions  CircleOptions
+	BoxOptions     BoxOptions
+	PolygonOptions PolygonOptions
+	SegmentOptions SegmentOptions
+}
+
+// NewCompound creates a new Object with a single body made up of multiple shapes. Each shape has its own offset,
+// size, material and color. The mass of the body is the sum of the masses of its shapes. If none of the shapes
+// has a mass, BodyOptions.Mass is divided evenly over the shapes that aren't sensors.
+//
+// The Offsets of the shapes are relative to BodyOptions.Position, but a body turns around its centre of mass: the
+// body of a dynamic compound is placed at the mass-weighted centroid of its shapes, and the shapes are moved to
+// stay where they were laid out. The body's Position is therefore only the same as BodyOptions.Position if the
+// shapes are balanced around it.
+//
+// The Elasticity, Friction and shape-specific attributes in BodyOptions are ignored: these are set per shape.
+// NewCompound returns an error if a dynamic compound has no shapes, if a shape has an unsupported type, or if the
+// vertices of a polygon shape aren't valid (see NewPolygon).
+func NewCompound(options DrawableOptions, shapes ...ShapeSpec) (*Object, error) {
+	if len(shapes) == 0 && !options.BodyOptions.StaticBody {
+		// chipmunk needs the shapes for the body's moment of inertia
+		return nil, errors.New("compound: a dynamic compound needs at least one shape")
+	}
+	var mass vect.Float
+	for _, spec := range shapes {
+		mass += spec.Mass
+	}
+	if mass == 0 {
+		// sensors get no mass, unless all shapes are sensors
+		var solid int
+		for _, spec := range shapes {
+			if !spec.Sensor {
+				solid++
+			}
+		}
+		share := solid
+		if share == 0 {
+			share = len(shapes)
+		}
+		shapes = append([]ShapeSpec(nil), shapes...)
+		for i := range shapes {
+			if !shapes[i].Sensor || solid == 0 {
+				shapes[i].Mass = options.BodyOptions.Mass / vect.Float(share)
+			}
+		}
+		mass = options.BodyOptions.Mass
+	}
+	if !options.BodyOptions.StaticBody && mass > 0 {
+		// chipmunk applies gravity at, and turns bodies around, their origin
+		var centre vect.Vect
+		for _, spec := range shapes {
+			centre = vect.Add(centre, vect.Mult(spec.centroid(), spec.Mass))
+		}
+		centre = vect.Mult(centre, 1/mass)
+		// shapes that are already balanced, e.g. those of a saved scene, aren't moved by rounding errors
+		if vect.Length(centre) > balanced {
+			shapes = append([]ShapeSpec(nil), shapes...)
+			for i := range shapes {
+				shapes[i].Offset = vect.Sub(shapes[i].Offset, centre)
+			}
+			origin := bodyState{position: options.BodyOptions.Position, angle: options.BodyOptions.Angle}
+			options.BodyOptions.Position = origin.toWorld(centre)
+		}
+	}
+
+	built := make([]*chipmunk.Shape, len(shapes))
+	for i, spec := range shapes {
+		shape, err := spec.shape()
+		if err != nil {
+			return nil, fmt.Errorf("compound: shape %d: %w", i, err)
+		}
+		built[i] = shape
+	}
+
+	var body *chipmunk.Body
+	if options.BodyOptions.StaticBody {
+		body = chipmunk.NewBodyStatic()
+	} else {
+		var i vect.Float
+		for j, spec := range shapes {
+			i += moment(built[j], spec.Mass)
+		}
+		body = chipmunk.NewBody(mass, i)
+	}
+	for i, spec := range shapes {
+		shape := built[i]
+		shape.SetElasticity(spec.Elasticity)
+		shape.SetFriction(spec.Friction)
+		shape.IsSensor = spec.Sensor
+		shape.UserData = spec
+		body.AddShape(shape)
+	}
+	body.SetPosition(options.BodyOptions.Position)
+	body.SetVelocity(float32(options.BodyOptions.Velocity.X), float32(options.BodyOptions.Velocity.Y))
+	body.SetAngle(options.BodyOptions.Angle)
+
+	options.BodyOptions.Mass = mass
+	return NewObject(body, options), nil
+}
+
+// centroid returns the centre of the shape, relative to the Object's Position
+func (spec ShapeSpec) centroid() vect.Vect {
+	switch spec.Type {
+	case chipmunk.ShapeType_Polygon:
+		if validatePolygon(spec.PolygonOptions.Vertices) != nil {
+			// reported when the shape is built
+			return spec.Offset
+		}
+		return vect.Add(spec.Offset, centroid(spec.PolygonOptions.Vertices))
+	case chipmunk.ShapeType_Segment:
+		return vect.Add(spec.Offset, vect.Mult(vect.Add(spec.SegmentOptions.A, spec.SegmentOptions.B), 0.5))
+	default:
+		return spec.Offset
+	}
+}
+
+// shape creates the chipmunk.Shape described by the ShapeSpec
+func (spec ShapeSpec) shape() (*chipmunk.Shape, error) {
+	switch spec.Type {
+	case chipmunk.ShapeType_Circle:
+		return chipmunk.NewCircle(spec.Offset, spec.CircleOptions.Radius), nil
+	case chipmunk.ShapeType_Box:
+		return chipmunk.NewBox(spec.Offset, spec.BoxOptions.Width, spec.BoxOptions.Height), nil
+	case chipmunk.ShapeType_Polygon:
+		if err := validatePolygon(spec.PolygonOptions.Vertices); err != nil {
+			return nil, err
+		}
+		return chipmunk.NewPolygon(clockwise(spec.PolygonOptions.Vertices), spec.Offset), nil
+	case chipmunk.ShapeType_Segment:
+		return chipmunk.NewSegment(
+			vect.Add(spec.Offset, spec.SegmentOptions.A),
+			vect.Add(spec.Offset, spec.SegmentOptions.B),
+			spec.SegmentOptions.Radius,
+		), nil
+	default:
+		return nil, fmt.Errorf("unsupported shape type: %d", spec.Type)
+	}
+}
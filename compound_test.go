@@ -0,0 +1,104 @@
+package pixelmunk
+
+import (
+	"github.com/vova616/chipmunk"
+	"github.com/vova616/chipmunk/vect"
+	"math"
+	"testing"
+)
+
+func TestNewCompound(t *testing.T) {
+	tests := []struct {
+		name    string
+		shapes  []ShapeSpec
+		wantErr bool
+	}{
+		{
+			name: "circle and box",
+			shapes: []ShapeSpec{
+				{Type: chipmunk.ShapeType_Circle, Offset: vect.Vect{X: -10}, CircleOptions: CircleOptions{Radius: 5}},
+				{Type: chipmunk.ShapeType_Box, Offset: vect.Vect{X: 10}, BoxOptions: BoxOptions{Width: 10, Height: 10}},
+			},
+		},
+		{
+			name: "triangle",
+			shapes: []ShapeSpec{{
+				Type:           chipmunk.ShapeType_Polygon,
+				PolygonOptions: PolygonOptions{Vertices: []vect.Vect{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 0, Y: 10}}},
+			}},
+		},
+		{
+			name: "concave polygon",
+			shapes: []ShapeSpec{
+				{Type: chipmunk.ShapeType_Circle, CircleOptions: CircleOptions{Radius: 5}},
+				{
+					Type:           chipmunk.ShapeType_Polygon,
+					PolygonOptions: PolygonOptions{Vertices: []vect.Vect{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}, {X: 5, Y: 2}, {X: 0, Y: 10}}},
+				},
+			},
+			wantErr: true,
+		},
+		{name: "no shapes", wantErr: true},
+		{name: "unsupported type", shapes: []ShapeSpec{{Type: chipmunk.ShapeType(99)}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			object, err := NewCompound(DrawableOptions{BodyOptions: BodyOptions{Mass: 2}}, tt.shapes...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewCompound: got error %v, want error: %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if object != nil {
+					t.Error("NewCompound returned an Object with an error")
+				}
+				return
+			}
+			if got := len(object.GetBody().Shapes); got != len(tt.shapes) {
+				t.Errorf("shapes: got %d, want %d", got, len(tt.shapes))
+			}
+			if m := object.GetBody().Mass(); m != 2 {
+				t.Errorf("mass: got %v, want 2", m)
+			}
+		})
+	}
+}
+
+func TestNewCompound_centreOfMass(t *testing.T) {
+	// a heavy ball and a light ball on a rod, laid out around Position
+	shapes := []ShapeSpec{
+		{Type: chipmunk.ShapeType_Circle, Offset: vect.Vect{X: 10}, Mass: 3, CircleOptions: CircleOptions{Radius: 2}},
+		{Type: chipmunk.ShapeType_Circle, Offset: vect.Vect{X: -10}, Mass: 1, CircleOptions: CircleOptions{Radius: 2}},
+	}
+	position := vect.Vect{X: 100, Y: 100}
+	object, err := NewCompound(DrawableOptions{BodyOptions: BodyOptions{Position: position, Angle: math.Pi / 2}}, shapes...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := object.GetBody()
+	if got, want := body.Position(), (vect.Vect{X: 100, Y: 105}); !approx(got.X, want.X) || !approx(got.Y, want.Y) {
+		t.Errorf("position: got %v, want %v", got, want)
+	}
+	// the balls stay where they were laid out
+	for i, want := range []vect.Vect{{X: 100, Y: 110}, {X: 100, Y: 90}} {
+		got := toWorld(body, body.Shapes[i].GetAsCircle().Position)
+		if !approx(got.X, want.X) || !approx(got.Y, want.Y) {
+			t.Errorf("shape %d: got %v, want %v", i, got, want)
+		}
+	}
+
+	// hung from Position, the heavy side tips down
+	w := NewWorld("compound", 0, 0, 200, 200)
+	w.Space.Gravity = vect.Vect{Y: -900}
+	anchor := NewCircle(DrawableOptions{BodyOptions: BodyOptions{StaticBody: true, Position: position, CircleOptions: CircleOptions{Radius: 1}}})
+	object, err = NewCompound(DrawableOptions{BodyOptions: BodyOptions{Position: position}}, shapes...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Add(object, NewJointWithAnchor(anchor, object, vect.Vect{}, toBody(object.GetBody(), position), DrawableOptions{}))
+	for range 30 {
+		w.Step(w.physicsDt())
+	}
+	if angle := object.GetBody().Angle(); angle >= -0.1 {
+		t.Errorf("angle: got %v, want the heavy side to tip down", angle)
+	}
+}
@@ -0,0 +1,90 @@
+package pixelmunk
+
+import (
+	"github.com/gopxl/pixel/v2"
+	"github.com/gopxl/pixel/v2/ext/imdraw"
+	"github.com/vova616/chipmunk"
+	"math"
+)
+
+// baseConstraint holds the parts that all constraint Drawables have in common
+type baseConstraint struct {
+	constraint chipmunk.Constraint
+	options    DrawableOptions
+}
+
+// GetType returns the type of drawable
+func (c baseConstraint) GetType() DrawableType {
+	return DrawableJoint
+}
+
+// GetBody returns nil: a constraint is not a body
+func (c baseConstraint) GetBody() *chipmunk.Body {
+	return nil
+}
+
+// GetConstraint returns the chipmunk.Constraint that the Drawable represents
+func (c baseConstraint) GetConstraint() chipmunk.Constraint {
+	return c.constraint
+}
+
+// GetOptions returns the DrawableOptions that were used to create the constraint
+func (c baseConstraint) GetOptions() DrawableOptions {
+	return c.options
+}
+
+// bodies returns the two bodies joined by the constraint
+func (c baseConstraint) bodies() (*chipmunk.Body, *chipmunk.Body) {
+	basic := c.constraint.Constraint()
+	return basic.BodyA, basic.BodyB
+}
+
+// anchorRadius is the radius of the dots that mark where a constraint is attached to a body
+const anchorRadius = 3.0
+
+// rotaryRadius is the radius of the arcs that show the state of a rotary constraint
+const rotaryRadius = 15.0
+
+func drawAnchor(imd *imdraw.IMDraw, p pixel.Vec) {
+	imd.Push(p)
+	imd.Circle(anchorRadius, 0)
+}
+
+func drawLine(imd *imdraw.IMDraw, a, b pixel.Vec, thickness float64) {
+	imd.Push(a, b)
+	imd.Line(max(thickness, 1))
+}
+
+// drawDashedLine draws a line from a to b, made up of dashes of the given length
+func drawDashedLine(imd *imdraw.IMDraw, a, b pixel.Vec, dash, thickness float64) {
+	length := b.Sub(a).Len()
+	if length == 0 {
+		return
+	}
+	step := b.Sub(a).Scaled(dash / length)
+	for d := 0.0; d < length; d += 2 * dash {
+		end := a.Add(step)
+		if d+dash > length {
+			end = b
+		}
+		drawLine(imd, a, end, thickness)
+		a = a.Add(step.Scaled(2))
+	}
+}
+
+// drawArc draws an arc around the centre, from angle low to angle high
+func drawArc(imd *imdraw.IMDraw, centre pixel.Vec, radius, low, high, thickness float64) {
+	if low == high {
+		return
+	}
+	if low > high {
+		low, high = high, low
+	}
+	imd.Push(centre)
+	imd.CircleArc(radius, low, math.Min(high, low+2*math.Pi), max(thickness, 1))
+}
+
+// drawSpoke draws a line from the centre, in the direction of angle
+func drawSpoke(imd *imdraw.IMDraw, centre pixel.Vec, radius, angle, thickness float64) {
+	drawLine(imd, centre, centre.Add(pixel.V(radius, 0).Rotated(angle)), thickness)
+}
@@ -0,0 +1,86 @@
+package pixelmunk
+
+import (
+	"fmt"
+	"github.com/gopxl/pixel/v2"
+)
+
+const (
+	minTimeScale = 1.0 / 16
+	maxTimeScale = 16
+)
+
+// debugKeys are the key bindings that the default run loop handles unless World.DisableDebugKeys is set
+var debugKeys = []keyBinding{
+	{button: pixel.KeyP, event: InputPressed, action: (*World).togglePause},
+	{button: pixel.KeyN, event: InputRepeated, action: (*World).StepOnce},
+	{button: pixel.KeyEqual, event: InputRepeated, action: (*World).faster},
+	{button: pixel.KeyKPAdd, event: InputRepeated, action: (*World).faster},
+	{button: pixel.KeyMinus, event: InputRepeated, action: (*World).slower},
+	{button: pixel.KeyKPSubtract, event: InputRepeated, action: (*World).slower},
+	{button: pixel.KeyBackspace, event: InputHeld, action: (*World).Rewind},
+}
+
+// Pause freezes the simulation in the default run loop. The World is still drawn and input is still handled.
+func (w *World) Pause() {
+	w.paused = true
+}
+
+// Resume continues a paused simulation
+func (w *World) Resume() {
+	w.paused = false
+	w.stepOnce = false
+}
+
+// Paused reports whether the simulation is paused
+func (w *World) Paused() bool {
+	return w.paused
+}
+
+// StepOnce pauses the simulation and advances it by a single step in the next frame of the default run loop
+func (w *World) StepOnce() {
+	w.paused = true
+	w.stepOnce = true
+}
+
+func (w *World) togglePause() {
+	if w.paused {
+		w.Resume()
+	} else {
+		w.Pause()
+	}
+}
+
+func (w *World) faster() {
+	w.TimeScale = min(w.timeScale()*2, maxTimeScale)
+}
+
+func (w *World) slower() {
+	w.TimeScale = max(w.timeScale()/2, minTimeScale)
+}
+
+func (w *World) timeScale() float64 {
+	if w.TimeScale <= 0 {
+		return 1
+	}
+	return w.TimeScale
+}
+
+// status describes the pace of the World and the state of its replay, for the window title
+func (w *World) status() string {
+	return w.pace() + w.replayStatus()
+}
+
+// pace describes whether the World is paused or rewinding, and its time scale
+func (w *World) pace() string {
+	switch {
+	case w.rewinding:
+		return " [rewind]"
+	case w.paused:
+		return " [paused]"
+	case w.timeScale() != 1:
+		return fmt.Sprintf(" [x%g]", w.timeScale())
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,112 @@
+package pixelmunk
+
+import (
+	"github.com/gopxl/pixel/v2"
+	"github.com/gopxl/pixel/v2/ext/imdraw"
+	"github.com/vova616/chipmunk"
+	"github.com/vova616/chipmunk/vect"
+	"golang.org/x/image/colornames"
+	"image/color"
+	"math"
+)
+
+// MouseDragOptions configures dragging Objects with the mouse in the default run loop
+type MouseDragOptions struct {
+	Enabled bool
+	// Button that picks up the Object under the mouse. Defaults to the left mouse button.
+	Button pixel.Button
+	// MaxForce is the maximum force with which the mouse pulls the Object. Zero means no limit.
+	MaxForce vect.Float
+	// Color of the line between the mouse and the dragged Object. Defaults to white.
+	Color color.Color
+}
+
+// mouseDrag is an Object being dragged: a pivot joint attaches the Object to a static body that follows the mouse.
+// The mouse body isn't part of the Space, so it doesn't collide with anything.
+type mouseDrag struct {
+	object *Object
+	mouse  *chipmunk.Body
+	joint  *chipmunk.PivotJoint
+}
+
+// updateMouseDrag picks up, moves and releases the dragged Object, based on the state of the mouse. handleInput
+// must be called first, to update the mouse position. While replaying, the recorded state of the mouse is used
+// instead.
+func (w *World) updateMouseDrag(in input, dt vect.Float) {
+	if w.Replaying() {
+		return
+	}
+	pressed, justPressed := in.Pressed(w.MouseDrag.Button), in.JustPressed(w.MouseDrag.Button)
+	if w.MouseDrag.Enabled && (pressed || justPressed || w.drag != nil) {
+		w.recordInput(replayInput{Drag: &replayDrag{Pressed: pressed, JustPressed: justPressed}})
+	}
+	w.dragMouse(w.mousePosition, pressed, justPressed, dt)
+}
+
+// dragMouse picks up, moves and releases the dragged Object
+func (w *World) dragMouse(position vect.Vect, pressed, justPressed bool, dt vect.Float) {
+	if !w.MouseDrag.Enabled {
+		w.releaseMouseDrag()
+		return
+	}
+	switch {
+	case w.drag == nil:
+		if justPressed {
+			w.startMouseDrag(position)
+		}
+	case !pressed:
+		w.releaseMouseDrag()
+	default:
+		// moving the mouse body with a velocity makes the dragged Object follow the mouse smoothly
+		velocity := vect.Mult(vect.Sub(position, w.drag.mouse.Position()), 1/dt)
+		w.drag.mouse.SetPosition(position)
+		w.drag.mouse.SetVelocity(float32(velocity.X), float32(velocity.Y))
+		w.drag.joint.MaxForce = w.MouseDrag.maxForce()
+	}
+}
+
+// startMouseDrag picks up the topmost dynamic Object at the position
+func (w *World) startMouseDrag(position vect.Vect) {
+	objects := w.QueryPoint(position)
+	for i := len(objects) - 1; i >= 0; i-- {
+		body := objects[i].GetBody()
+		if body.IsStatic() {
+			continue
+		}
+		mouse := chipmunk.NewBodyStatic()
+		mouse.SetPosition(position)
+		joint := chipmunk.NewPivotJointAnchor(mouse, body, vect.Vect{}, toBody(body, position))
+		joint.MaxForce = w.MouseDrag.maxForce()
+		w.Space.AddConstraint(joint)
+		w.drag = &mouseDrag{object: objects[i], mouse: mouse, joint: joint}
+		return
+	}
+}
+
+// releaseMouseDrag drops the dragged Object, if any
+func (w *World) releaseMouseDrag() {
+	if w.drag != nil {
+		w.Space.RemoveConstraint(w.drag.joint)
+		w.drag = nil
+	}
+}
+
+func (o MouseDragOptions) maxForce() vect.Float {
+	if o.MaxForce <= 0 {
+		return vect.Float(math.Inf(1))
+	}
+	return o.MaxForce
+}
+
+// draw draws a line from the mouse to the point where the Object was picked up, with the Object at the position
+// and angle returned by pose
+func (d *mouseDrag) draw(imd *imdraw.IMDraw, c color.Color, pose poseFunc) {
+	if c == nil {
+		c = colornames.White
+	}
+	mouse := toPixel(d.mouse.Position())
+	anchor := toPixel(pose(d.object.GetBody()).toWorld(d.joint.Anchor2))
+	imd.Color = c
+	drawLine(imd, mouse, anchor, 1)
+	drawAnchor(imd, anchor)
+}
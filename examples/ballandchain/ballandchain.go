@@ -6,8 +6,8 @@ import (
 	"github.com/gopxl/pixel/v2/backends/opengl"
 	"github.com/vova616/chipmunk/vect"
 	"golang.org/x/image/colornames"
+	"log"
 	"math/rand"
-	"time"
 )
 
 func main() {
@@ -16,10 +16,11 @@ func main() {
 }
 
 type App struct {
-	world      *pixelmunk.World
-	anchor     *pixelmunk.Object
-	fire       bool
-	fireTicker *time.Ticker
+	world  *pixelmunk.World
+	anchor *pixelmunk.Object
+	fire   bool
+	// time since the last bullet was fired
+	elapsed vect.Float
 }
 
 func createWorld(x, y float64) (app App) {
@@ -28,9 +29,12 @@ func createWorld(x, y float64) (app App) {
 	}
 
 	app.world.Space.Gravity = vect.Vect{Y: -981}
-	app.world.FrameRate = 120
-	app.world.RunCallback = app.Process
-	//app.fireTicker = time.NewTicker(time.Second)
+	app.world.PhysicsHz = 120
+	app.world.StepCallback = app.Process
+	app.world.BindKey(pixel.KeySpace, pixelmunk.InputReleased, app.toggleFire)
+	app.world.OnJointBroken = func(joint pixelmunk.Drawable, impulse vect.Float) {
+		log.Printf("chain broken (impulse: %.0f)", impulse)
+	}
 
 	midX := vect.Float(x / 2)
 	midY := vect.Float(y * 3 / 4)
@@ -115,6 +119,8 @@ func createChain(anchor, ball *pixelmunk.Object) (links []*pixelmunk.Object, joi
 		Thickness: 1,
 		JointOptions: pixelmunk.JointOptions{
 			Draw: true,
+			// strong enough to hold the swinging ball, but a direct hit from a bullet may break the chain
+			BreakImpulse: 2.8e6,
 		},
 	}
 
@@ -147,24 +153,24 @@ func createChain(anchor, ball *pixelmunk.Object) (links []*pixelmunk.Object, joi
 	return
 }
 
-func (app *App) Process(win *opengl.Window) {
-	if win.JustReleased(pixel.KeySpace) {
-		if app.fire {
-			app.fire = false
-			app.fireTicker.Stop()
-		} else {
-			app.fire = true
-			app.fireBullet()
-			app.fireTicker = time.NewTicker(time.Second)
-		}
-	}
+func (app *App) toggleFire(_ *pixelmunk.World) {
+	app.fire = !app.fire
 	if app.fire {
-		select {
-		case <-app.fireTicker.C:
-			app.fireBullet()
-			app.cleanup()
-		default:
-		}
+		app.fireBullet()
+		app.elapsed = 0
+	}
+}
+
+func (app *App) Process(dt vect.Float) {
+	if !app.fire {
+		return
+	}
+	// a new bullet every second
+	app.elapsed += dt
+	if app.elapsed >= 1 {
+		app.elapsed -= 1
+		app.fireBullet()
+		app.cleanup()
 	}
 }
 
@@ -189,7 +195,7 @@ func (app *App) fireBullet() {
 			},
 		},
 	})
-	app.world.Add(bullet)
+	app.world.Spawn(bullet)
 }
 
 func (app *App) cleanup() {
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"github.com/clambin/pixelmunk"
+	"github.com/gopxl/pixel/v2"
+	"github.com/gopxl/pixel/v2/backends/opengl"
+	"github.com/vova616/chipmunk/vect"
+	"golang.org/x/image/colornames"
+	"log"
+	"math"
+)
+
+const (
+	speed       = 10
+	engineForce = 1e6
+	coastForce  = 1e4
+	// the terrain is this many windows wide
+	screens = 4
+)
+
+func main() {
+	w := createWorld(1024, 768)
+	opengl.Run(w.Run)
+}
+
+type car struct {
+	chassis *pixelmunk.Object
+	motors  []*pixelmunk.Motor
+}
+
+func createWorld(x, y float64) (world *pixelmunk.World) {
+	world = pixelmunk.NewWorld("car (left/right to drive)", 0, 0, x, y)
+	world.Space.Gravity = vect.Vect{Y: -981}
+
+	// Terrain
+	const spacing = 32
+	heights := make([]vect.Float, screens*int(x)/spacing+1)
+	for i := range heights {
+		heights[i] = vect.Float(150 + 60*math.Sin(float64(i)/4))
+	}
+	heights[0], heights[len(heights)-1] = vect.Float(y), vect.Float(y)
+	terrain, err := pixelmunk.NewHeightmapTerrain(heights, spacing, pixelmunk.DrawableOptions{
+		Color: colornames.Green,
+		BodyOptions: pixelmunk.BodyOptions{
+			Friction: 1.0,
+			SegmentOptions: pixelmunk.SegmentOptions{
+				Radius: 4,
+			},
+		},
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	world.Add(terrain)
+
+	c := newCar(world, vect.Vect{X: 200, Y: 400})
+	world.RunCallback = c.drive
+
+	// the camera follows the car, without showing anything beyond the end of the terrain
+	world.Camera = &pixelmunk.Camera{
+		Follow:      c.chassis,
+		FollowSpeed: 5,
+		Limits:      pixel.R(0, 0, screens*x, y),
+	}
+
+	return
+}
+
+func newCar(world *pixelmunk.World, position vect.Vect) *car {
+	chassis := pixelmunk.NewBox(pixelmunk.DrawableOptions{
+		Color: colornames.Red,
+		BodyOptions: pixelmunk.BodyOptions{
+			Position:   position,
+			Mass:       10,
+			Friction:   0.5,
+			BoxOptions: pixelmunk.BoxOptions{Width: 160, Height: 30},
+		},
+	})
+	world.Add(chassis)
+
+	c := car{chassis: chassis}
+	for _, offset := range []vect.Vect{{X: -60, Y: -50}, {X: 60, Y: -50}} {
+		wheel := pixelmunk.NewCircle(pixelmunk.DrawableOptions{
+			Color: colornames.Gray,
+			BodyOptions: pixelmunk.BodyOptions{
+				Position:      vect.Add(position, offset),
+				Mass:          2,
+				Friction:      1.5,
+				CircleOptions: pixelmunk.CircleOptions{Radius: 25},
+			},
+		})
+		motor := pixelmunk.NewMotor(chassis, wheel, 0, pixelmunk.DrawableOptions{})
+		motor.SetMaxForce(coastForce)
+		world.Add(wheel,
+			pixelmunk.NewJointWithAnchor(chassis, wheel, offset, vect.Vect{}, pixelmunk.DrawableOptions{
+				Color:        colornames.Darkgray,
+				Thickness:    3,
+				JointOptions: pixelmunk.JointOptions{Draw: true},
+			}),
+			motor,
+		)
+		c.motors = append(c.motors, motor)
+	}
+	return &c
+}
+
+// drive sets the speed of the wheels from the arrow keys. The motors turn the wheels relative to the chassis:
+// a positive rate turns them clockwise, driving the car to the right.
+func (c *car) drive(win *opengl.Window) {
+	rate, force := vect.Float(0), vect.Float(coastForce)
+	switch {
+	case win.Pressed(pixel.KeyRight):
+		rate, force = speed, engineForce
+	case win.Pressed(pixel.KeyLeft):
+		rate, force = -speed, engineForce
+	}
+	for _, motor := range c.motors {
+		motor.SetRate(rate)
+		motor.SetMaxForce(force)
+	}
+}
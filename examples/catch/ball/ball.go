@@ -12,6 +12,9 @@ import (
 	"math/rand"
 )
 
+// CollisionType of a ball
+const CollisionType pixelmunk.CollisionType = 1
+
 // Ball represents a tennis ball
 type Ball struct {
 	*pixelmunk.Object
@@ -31,6 +34,7 @@ func NewBall(position vect.Vect, radius float32, color color.Color) (ball *Ball)
 				Elasticity:    0.9,
 				Friction:      2e8,
 				Type:          chipmunk.ShapeType_Circle,
+				CollisionType: CollisionType,
 				CircleOptions: pixelmunk.CircleOptions{Radius: radius},
 			},
 		}),
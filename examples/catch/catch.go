@@ -1,6 +1,7 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"github.com/clambin/pixelmunk"
 	"github.com/clambin/pixelmunk/examples/catch/ball"
@@ -9,8 +10,9 @@ import (
 	"github.com/gopxl/pixel/v2/backends/opengl"
 	"github.com/vova616/chipmunk/vect"
 	"golang.org/x/image/colornames"
+	"log"
 	"math/rand"
-	"time"
+	"os"
 )
 
 const (
@@ -22,21 +24,67 @@ const (
 )
 
 func main() {
+	record := flag.String("record", "", "record the game to `file`, e.g. to attach it to a bug report")
+	replay := flag.String("replay", "", "replay a game recorded in `file`")
+	flag.Parse()
+
 	app := createApp()
+	if *replay != "" {
+		if err := loadReplay(app.world, *replay); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if *record != "" {
+		if err := app.world.StartRecording(); err != nil {
+			log.Fatal(err)
+		}
+		app.world.OnClose = func() {
+			if err := saveReplay(app.world, *record); err != nil {
+				log.Print(err)
+			}
+		}
+	}
 	opengl.Run(app.world.Run)
 }
 
+func loadReplay(world *pixelmunk.World, filename string) error {
+	f, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+	return world.LoadReplay(f)
+}
+
+func saveReplay(world *pixelmunk.World, filename string) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	if err = world.SaveReplay(f); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
 type catch struct {
-	world *pixelmunk.World
-	cup   *cup.Cup
+	world  *pixelmunk.World
+	cup    *cup.Cup
+	caught map[*pixelmunk.Object]bool
+	// time since the last ball was added
+	elapsed vect.Float
 }
 
 func createApp() (app *catch) {
 	app = &catch{
-		world: pixelmunk.NewWorld("catch!", 0, 0, width, height),
+		world:  pixelmunk.NewWorld("catch!", 0, 0, width, height),
+		caught: make(map[*pixelmunk.Object]bool),
 	}
-	app.world.RunFunc = app.run
+	app.world.StepCallback = app.step
 	app.world.Space.Gravity = vect.Vect{X: 0, Y: -900}
+	app.world.BindKey(pixel.KeyRight, pixelmunk.InputReleased, func(*pixelmunk.World) { app.cup.SetDirection(1.0) })
+	app.world.BindKey(pixel.KeyLeft, pixelmunk.InputReleased, func(*pixelmunk.World) { app.cup.SetDirection(-1.0) })
 
 	// Floor
 	app.world.Add(pixelmunk.NewBox(pixelmunk.DrawableOptions{
@@ -55,33 +103,32 @@ func createApp() (app *catch) {
 	}))
 
 	// Cup
-	app.cup = cup.NewCup(vect.Vect{X: 400, Y: floorHeight + cupHeight/2}, cupWidth, cupHeight, colornames.Brown)
+	var err error
+	if app.cup, err = cup.NewCup(vect.Vect{X: 400, Y: floorHeight + cupHeight/2}, cupWidth, cupHeight, colornames.Brown); err != nil {
+		log.Fatal(err)
+	}
 	app.world.Add(app.cup)
 
+	// count every ball that touches the cup
+	app.world.OnCollisionBegin(pixelmunk.CollisionFilter{Type: ball.CollisionType, OtherType: cup.CollisionType}, func(c pixelmunk.Collision) bool {
+		app.caught[c.A] = true
+		return true
+	})
+
 	return
 }
 
-func (c *catch) run(win *opengl.Window) {
-	timer := time.Now()
-	frameTicker := time.NewTicker(time.Second / time.Duration(c.world.FrameRate))
-	ballTicker := time.NewTicker(1 * time.Second)
-
-	for !win.Closed() {
-		select {
-		case <-ballTicker.C:
-			c.addBall()
-			c.cleanup()
-		case <-frameTicker.C:
-			c.world.Space.Step(1.0 / vect.Float(c.world.FrameRate))
-			win.Clear(colornames.Black)
-			c.world.Draw(win)
-			win.Update()
-			c.processEvents(win)
-
-			win.SetTitle(fmt.Sprintf("gravity (%.1f fps)", 1/time.Now().Sub(timer).Seconds()))
-			timer = time.Now()
-		}
+func (c *catch) step(dt vect.Float) {
+	// a new ball every second
+	c.elapsed += dt
+	if c.elapsed >= 1 {
+		c.elapsed -= 1
+		c.addBall()
+		c.cleanup()
 	}
+	c.cup.Move()
+
+	c.world.Name = fmt.Sprintf("catch! in cup: %d, caught: %d", len(c.world.Occupants(c.cup.Object)), len(c.caught))
 }
 
 func (c *catch) addBall() {
@@ -89,26 +136,16 @@ func (c *catch) addBall() {
 		X: vect.Float(c.world.Bounds.Min.X + float64(rand.Intn(int(c.world.Bounds.Max.X-c.world.Bounds.Min.X)))),
 		Y: vect.Float(c.world.Bounds.Max.Y),
 	}
-	c.world.Add(ball.NewBall(pos, 20.0, colornames.Yellow))
+	// spawned, so a replay drops the ball in the same place
+	c.world.Spawn(ball.NewBall(pos, 20.0, colornames.Yellow))
 }
 
 func (c *catch) cleanup() {
-	for index := 0; index < len(c.world.Objects); index++ {
-		if c.world.Objects[index].GetBody().Position().Y < 0 {
-			c.world.Space.RemoveBody(c.world.Objects[index].GetBody())
-			c.world.Objects = append(c.world.Objects[:index], c.world.Objects[index+1:]...)
-			index--
+	var lost []pixelmunk.Drawable
+	for _, object := range c.world.Objects {
+		if object.GetBody().Position().Y < 0 {
+			lost = append(lost, object)
 		}
 	}
-}
-
-func (c *catch) processEvents(win *opengl.Window) {
-	if win.JustReleased(pixel.KeyRight) {
-		c.cup.SetDirection(1.0)
-	}
-	if win.JustReleased(pixel.KeyLeft) {
-		c.cup.SetDirection(-1.0)
-	}
-
-	c.cup.Move()
+	c.world.Remove(lost...)
 }
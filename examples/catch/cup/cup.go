@@ -8,6 +8,9 @@ import (
 	"image/color"
 )
 
+// CollisionType of the cup
+const CollisionType pixelmunk.CollisionType = 2
+
 // Cup represents the cup with which to catch the tennis balls
 type Cup struct {
 	*pixelmunk.Object
@@ -15,14 +18,25 @@ type Cup struct {
 }
 
 // NewCup creates a new Cup
-func NewCup(position vect.Vect, width, height vect.Float, color color.Color) (cup *Cup) {
-	return &Cup{
-		Object: pixelmunk.NewObject(
-			makeCupBody(position, width, height),
-			pixelmunk.DrawableOptions{Color: color},
-		),
-		Direction: 1.0,
+func NewCup(position vect.Vect, width, height vect.Float, color color.Color) (*Cup, error) {
+	object, err := pixelmunk.NewCompound(
+		pixelmunk.DrawableOptions{
+			Color: color,
+			BodyOptions: pixelmunk.BodyOptions{
+				Position:      position,
+				Mass:          1e12,
+				CollisionType: CollisionType,
+			},
+		},
+		getCupShapes(float64(width), float64(height))...,
+	)
+	if err != nil {
+		return nil, err
 	}
+	cup := &Cup{Object: object, Direction: 1.0}
+	cup.GetBody().IgnoreGravity = true
+	cup.GetBody().UserData = "cup"
+	return cup, nil
 }
 
 // SetDirection sets the direction in which the cup should move
@@ -37,26 +51,33 @@ func (cup *Cup) Move() {
 	cup.GetBody().SetPosition(pos)
 }
 
-func makeCupBody(position vect.Vect, width, height vect.Float) (body *chipmunk.Body) {
-	body = chipmunk.NewBody(1e12, 1e12)
-	body.IgnoreGravity = true
-	//body.SetVelocity(15, 0)
-	boxes := getCupBoxes(float64(width), float64(height))
-	for _, box := range boxes {
-		boxWidth := vect.Float(box.Max.X - box.Min.X)
-		boxHeight := vect.Float(box.Max.Y - box.Min.Y)
+func getCupShapes(width, height float64) (shapes []pixelmunk.ShapeSpec) {
+	for _, box := range getCupBoxes(width, height) {
 		x, y := box.Center().XY()
-		pos := vect.Vect{X: vect.Float(x), Y: vect.Float(y)}
-
-		b := chipmunk.NewBox(pos, boxWidth, boxHeight)
-		b.SetElasticity(0.4)
-		b.SetFriction(200)
-		body.AddShape(b)
+		shapes = append(shapes, pixelmunk.ShapeSpec{
+			Type:       chipmunk.ShapeType_Box,
+			Offset:     vect.Vect{X: vect.Float(x), Y: vect.Float(y)},
+			Elasticity: 0.4,
+			Friction:   200,
+			BoxOptions: pixelmunk.BoxOptions{
+				Width:  vect.Float(box.W()),
+				Height: vect.Float(box.H()),
+			},
+		})
 	}
-	body.SetPosition(position)
-	body.UserData = "cup"
 
-	return
+	// goal zone: detects the balls inside the cup
+	delta := width * 0.10
+	shapes = append(shapes, pixelmunk.ShapeSpec{
+		Type:   chipmunk.ShapeType_Box,
+		Offset: vect.Vect{Y: vect.Float(delta / 2)},
+		Sensor: true,
+		BoxOptions: pixelmunk.BoxOptions{
+			Width:  vect.Float(width - 2*delta),
+			Height: vect.Float(height - delta),
+		},
+	})
+	return shapes
 }
 
 func getCupBoxes(width, height float64) []pixel.Rect {
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"github.com/clambin/pixelmunk"
+	"github.com/gopxl/pixel/v2/backends/opengl"
+	"github.com/vova616/chipmunk"
+	"github.com/vova616/chipmunk/vect"
+	"golang.org/x/image/colornames"
+	"math"
+)
+
+func main() {
+	w := createWorld(1100, 800)
+	opengl.Run(w.Run)
+}
+
+var jointOptions = pixelmunk.DrawableOptions{
+	Color:     colornames.Yellow,
+	Thickness: 2,
+	JointOptions: pixelmunk.JointOptions{
+		Draw: true,
+	},
+}
+
+func createWorld(x, y float64) (world *pixelmunk.World) {
+	world = pixelmunk.NewWorld("constraints", 0, 0, x, y)
+	world.Space.Gravity = vect.Vect{Y: -981}
+	world.MouseDrag.Enabled = true
+	world.Window.Resizable = true
+
+	// invisible static body at the origin, so anchors on it are in world coordinates
+	ceiling := pixelmunk.NewObject(chipmunk.NewBodyStatic(), pixelmunk.DrawableOptions{})
+
+	column := func(i int) vect.Float { return vect.Float(80 + i*130) }
+	top := vect.Float(700)
+
+	// Pin joint: a rigid rod
+	pinned := ball(column(0), 500)
+	world.Add(pinned, pixelmunk.NewPinJoint(ceiling, pinned, vect.Vect{X: column(0) + 60, Y: top}, vect.Vect{}, jointOptions))
+
+	// Slide joint: a rope between 50 and 200 long
+	slid := ball(column(1)+40, 600)
+	world.Add(slid, pixelmunk.NewSlideJoint(ceiling, slid, vect.Vect{X: column(1), Y: top}, vect.Vect{}, 50, 200, jointOptions))
+
+	// Groove joint: the ball slides down a rail
+	grooved := ball(column(2)-50, top)
+	world.Add(grooved, pixelmunk.NewGrooveJoint(ceiling, grooved, vect.Vect{X: column(2) - 50, Y: top}, vect.Vect{X: column(2) + 50, Y: top - 200}, vect.Vect{}, jointOptions))
+
+	// Damped spring
+	sprung := ball(column(3), top-100)
+	world.Add(sprung, pixelmunk.NewSpring(ceiling, sprung, vect.Vect{X: column(3), Y: top}, vect.Vect{}, 100, 50, 0.5, jointOptions))
+
+	// Damped rotary spring: the wheel winds back to its rest angle
+	wound := wheel(world, ceiling, column(4), 400, 80)
+	wound.GetBody().SetAngularVelocity(10)
+	world.Add(pixelmunk.NewRotarySpring(ceiling, wound, 0, 20000, 500, jointOptions))
+
+	// Rotary limit: the wheel can only turn a quarter
+	limited := wheel(world, ceiling, column(5), 400, 80)
+	limited.GetBody().SetAngularVelocity(5)
+	world.Add(pixelmunk.NewRotaryLimit(ceiling, limited, -math.Pi/4, math.Pi/4, jointOptions))
+
+	// Ratchet: a pendulum that can only swing one way
+	arm := pixelmunk.NewBox(pixelmunk.DrawableOptions{
+		Color: colornames.Orange,
+		BodyOptions: pixelmunk.BodyOptions{
+			Position:   vect.Vect{X: column(6) + 40, Y: 400},
+			Mass:       1,
+			BoxOptions: pixelmunk.BoxOptions{Width: 100, Height: 15},
+		},
+	})
+	world.Add(arm,
+		pixelmunk.NewJointWithAnchor(ceiling, arm, vect.Vect{X: column(6), Y: 400}, vect.Vect{X: -40}, pixelmunk.DrawableOptions{}),
+		pixelmunk.NewRatchet(ceiling, arm, 0, math.Pi/8, jointOptions),
+	)
+
+	// Motor and gears: the motor drives the small wheel, which drives the large one at half the speed
+	driver := wheel(world, ceiling, column(7), 450, 60)
+	driven := wheel(world, ceiling, column(7), 250, 120)
+	world.Add(
+		pixelmunk.NewMotor(ceiling, driver, -2, jointOptions),
+		pixelmunk.NewGear(driver, driven, 0, 2, jointOptions),
+	)
+
+	return
+}
+
+func ball(x, y vect.Float) *pixelmunk.Object {
+	return pixelmunk.NewCircle(pixelmunk.DrawableOptions{
+		Color: colornames.Orange,
+		BodyOptions: pixelmunk.BodyOptions{
+			Position:      vect.Vect{X: x, Y: y},
+			Mass:          1,
+			CircleOptions: pixelmunk.CircleOptions{Radius: 20},
+		},
+	})
+}
+
+// wheel adds a bar to the world that turns around its centre
+func wheel(world *pixelmunk.World, ceiling *pixelmunk.Object, x, y, width vect.Float) *pixelmunk.Object {
+	w := pixelmunk.NewBox(pixelmunk.DrawableOptions{
+		Color: colornames.Orange,
+		BodyOptions: pixelmunk.BodyOptions{
+			Position:   vect.Vect{X: x, Y: y},
+			Mass:       1,
+			BoxOptions: pixelmunk.BoxOptions{Width: width, Height: 15},
+		},
+	})
+	world.Add(w, pixelmunk.NewJointWithAnchor(ceiling, w, vect.Vect{X: x, Y: y}, vect.Vect{}, pixelmunk.DrawableOptions{}))
+	return w
+}
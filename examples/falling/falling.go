@@ -7,6 +7,7 @@ import (
 	"github.com/gopxl/pixel/v2/ext/imdraw"
 	"github.com/vova616/chipmunk/vect"
 	"golang.org/x/image/colornames"
+	"log"
 	"math"
 )
 
@@ -85,6 +86,26 @@ func createWorld(x, y float64) (world *pixelmunk.World) {
 		},
 	}))
 
+	// Wedge
+	wedge, err := pixelmunk.NewPolygon(pixelmunk.DrawableOptions{
+		Color:          colornames.Yellow,
+		CustomDrawFunc: []pixelmunk.CustomDrawFunc{drawVelocity},
+		BodyOptions: pixelmunk.BodyOptions{
+			Position:   vect.Vect{X: 350, Y: 1500},
+			Angle:      math.Pi / 5,
+			Mass:       10e3,
+			Elasticity: 0.4,
+			Friction:   1.0,
+			PolygonOptions: pixelmunk.PolygonOptions{
+				Vertices: []vect.Vect{{X: -60, Y: -30}, {X: 60, Y: -30}, {X: -60, Y: 30}},
+			},
+		},
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	world.Add(wedge)
+
 	return
 }
 
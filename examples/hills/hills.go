@@ -0,0 +1,59 @@
+package main
+
+import (
+	"github.com/clambin/pixelmunk"
+	"github.com/gopxl/pixel/v2/backends/opengl"
+	"github.com/vova616/chipmunk/vect"
+	"golang.org/x/image/colornames"
+	"log"
+	"math"
+)
+
+func main() {
+	w := createWorld(1024, 1080)
+	opengl.Run(w.Run)
+}
+
+func createWorld(x, y float64) (world *pixelmunk.World) {
+	world = pixelmunk.NewWorld("hills", 0, 0, x, y)
+	world.Space.Gravity = vect.Vect{Y: -981}
+
+	// Terrain
+	const spacing = 32
+	heights := make([]vect.Float, int(x)/spacing+1)
+	for i := range heights {
+		heights[i] = vect.Float(200 + 80*math.Sin(float64(i)/3) + 40*math.Sin(float64(i)/1.3))
+	}
+	terrain, err := pixelmunk.NewHeightmapTerrain(heights, spacing, pixelmunk.DrawableOptions{
+		Color: colornames.Green,
+		BodyOptions: pixelmunk.BodyOptions{
+			Elasticity: 0.3,
+			Friction:   1.0,
+			SegmentOptions: pixelmunk.SegmentOptions{
+				Radius: 4,
+			},
+		},
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	world.Add(terrain)
+
+	// Balls
+	for i := 0; i < 10; i++ {
+		world.Add(pixelmunk.NewCircle(pixelmunk.DrawableOptions{
+			Color: colornames.Orange,
+			BodyOptions: pixelmunk.BodyOptions{
+				Position:   vect.Vect{X: vect.Float(50 + i*100), Y: vect.Float(y) - 100},
+				Mass:       1,
+				Elasticity: 0.5,
+				Friction:   1.0,
+				CircleOptions: pixelmunk.CircleOptions{
+					Radius: 15,
+				},
+			},
+		}))
+	}
+
+	return
+}
@@ -61,17 +61,16 @@ func createWorld(x, y float64) (world *pixelmunk.World) {
 			},
 		})
 
-	wall := pixelmunk.NewBox(pixelmunk.DrawableOptions{
-		Color:     colornames.Blue,
-		Thickness: 0,
+	wall := pixelmunk.NewSegment(pixelmunk.DrawableOptions{
+		Color: colornames.Blue,
 		BodyOptions: pixelmunk.BodyOptions{
 			StaticBody: true,
 			Position:   vect.Vect{X: 20, Y: midY},
-			Mass:       1e3,
 			Elasticity: 0.99,
-			BoxOptions: pixelmunk.BoxOptions{
-				Width:  40,
-				Height: vect.Float(y),
+			SegmentOptions: pixelmunk.SegmentOptions{
+				A:      vect.Vect{Y: -midY},
+				B:      vect.Vect{Y: midY},
+				Radius: 20,
 			},
 		},
 	})
@@ -15,7 +15,11 @@ func main() {
 func createWorld(x, y float64) (world *pixelmunk.World) {
 	world = pixelmunk.NewWorld("ragdoll", 0, 0, x, y)
 	world.Space.Gravity = vect.Vect{Y: -981}
-	world.FrameRate = 120
+	world.PhysicsHz = 120
+	// grab the ragdoll and throw it around
+	world.MouseDrag.Enabled = true
+	// hold backspace to undo the last throws
+	world.RewindSeconds = 10
 
 	// Floor
 	world.Add(pixelmunk.NewBox(pixelmunk.DrawableOptions{
@@ -40,6 +44,9 @@ func createWorld(x, y float64) (world *pixelmunk.World) {
 
 const jointThickness = 0
 
+// ragdollGroup keeps the parts of the doll from colliding with each other, so the joints can move freely
+const ragdollGroup = 1
+
 type RagDoll struct {
 	Head      *pixelmunk.Object
 	Torso     *pixelmunk.Object
@@ -57,11 +64,12 @@ func NewRagDoll(w *pixelmunk.World, x, y float64) (ragdoll RagDoll) {
 	ragdoll.Head = pixelmunk.NewCircle(pixelmunk.DrawableOptions{
 		Color: colornames.Orange,
 		BodyOptions: pixelmunk.BodyOptions{
-			Position:      vect.Vect{X: startX, Y: startY},
-			Mass:          1e2,
-			Elasticity:    0.2,
-			Friction:      1,
-			CircleOptions: pixelmunk.CircleOptions{Radius: 15},
+			Position:       vect.Vect{X: startX, Y: startY},
+			Mass:           1e2,
+			Elasticity:     0.2,
+			Friction:       1,
+			CollisionGroup: ragdollGroup,
+			CircleOptions:  pixelmunk.CircleOptions{Radius: 15},
 		},
 	})
 	w.Add(ragdoll.Head)
@@ -69,10 +77,11 @@ func NewRagDoll(w *pixelmunk.World, x, y float64) (ragdoll RagDoll) {
 	ragdoll.Torso = pixelmunk.NewBox(pixelmunk.DrawableOptions{
 		Color: colornames.Blue,
 		BodyOptions: pixelmunk.BodyOptions{
-			Position:   vect.Vect{X: startX, Y: startY - 15 - 30},
-			Mass:       1e4,
-			Elasticity: 0.5,
-			Friction:   1,
+			Position:       vect.Vect{X: startX, Y: startY - 15 - 30},
+			Mass:           1e4,
+			Elasticity:     0.5,
+			Friction:       1,
+			CollisionGroup: ragdollGroup,
 			BoxOptions: pixelmunk.BoxOptions{
 				Width:  40,
 				Height: 60,
@@ -94,10 +103,11 @@ func NewRagDoll(w *pixelmunk.World, x, y float64) (ragdoll RagDoll) {
 	ragdoll.Arms = append(ragdoll.Arms, pixelmunk.NewBox(pixelmunk.DrawableOptions{
 		Color: colornames.Orange,
 		BodyOptions: pixelmunk.BodyOptions{
-			Position:   vect.Vect{X: startX - 20 - 20, Y: startY - 15 - 5},
-			Mass:       1e3,
-			Elasticity: 0.8,
-			Friction:   1,
+			Position:       vect.Vect{X: startX - 20 - 20, Y: startY - 15 - 5},
+			Mass:           1e3,
+			Elasticity:     0.8,
+			Friction:       1,
+			CollisionGroup: ragdollGroup,
 			BoxOptions: pixelmunk.BoxOptions{
 				Width:  40,
 				Height: 10,
@@ -114,10 +124,11 @@ func NewRagDoll(w *pixelmunk.World, x, y float64) (ragdoll RagDoll) {
 	ragdoll.Arms = append(ragdoll.Arms, pixelmunk.NewBox(pixelmunk.DrawableOptions{
 		Color: colornames.Orange,
 		BodyOptions: pixelmunk.BodyOptions{
-			Position:   vect.Vect{X: startX + 20 + 20, Y: startY - 15 - 5},
-			Mass:       1e3,
-			Elasticity: 0.8,
-			Friction:   1,
+			Position:       vect.Vect{X: startX + 20 + 20, Y: startY - 15 - 5},
+			Mass:           1e3,
+			Elasticity:     0.8,
+			Friction:       1,
+			CollisionGroup: ragdollGroup,
 			BoxOptions: pixelmunk.BoxOptions{
 				Width:  40,
 				Height: 10,
@@ -134,10 +145,11 @@ func NewRagDoll(w *pixelmunk.World, x, y float64) (ragdoll RagDoll) {
 	ragdoll.Legs = append(ragdoll.Legs, pixelmunk.NewBox(pixelmunk.DrawableOptions{
 		Color: colornames.Blue,
 		BodyOptions: pixelmunk.BodyOptions{
-			Position:   vect.Vect{X: startX - 15, Y: startY - 15 - 60 - 20},
-			Mass:       5e3,
-			Elasticity: 0.8,
-			Friction:   1,
+			Position:       vect.Vect{X: startX - 15, Y: startY - 15 - 60 - 20},
+			Mass:           5e3,
+			Elasticity:     0.8,
+			Friction:       1,
+			CollisionGroup: ragdollGroup,
 			BoxOptions: pixelmunk.BoxOptions{
 				Width:  10,
 				Height: 40,
@@ -154,10 +166,11 @@ func NewRagDoll(w *pixelmunk.World, x, y float64) (ragdoll RagDoll) {
 	ragdoll.Legs = append(ragdoll.Legs, pixelmunk.NewBox(pixelmunk.DrawableOptions{
 		Color: colornames.Blue,
 		BodyOptions: pixelmunk.BodyOptions{
-			Position:   vect.Vect{X: startX + 15, Y: startY - 15 - 60 - 20},
-			Mass:       5e3,
-			Elasticity: 0.8,
-			Friction:   1,
+			Position:       vect.Vect{X: startX + 15, Y: startY - 15 - 60 - 20},
+			Mass:           5e3,
+			Elasticity:     0.8,
+			Friction:       1,
+			CollisionGroup: ragdollGroup,
 			BoxOptions: pixelmunk.BoxOptions{
 				Width:  10,
 				Height: 40,
@@ -0,0 +1,100 @@
+package pixelmunk
+
+import (
+	"github.com/gopxl/pixel/v2/ext/imdraw"
+	"github.com/vova616/chipmunk"
+	"github.com/vova616/chipmunk/vect"
+)
+
+// Gear keeps the angular velocities of two bodies at a fixed ratio, as if they were connected by a pair of gears
+type Gear struct {
+	baseConstraint
+	gear *gearConstraint
+}
+
+var _ Drawable = &Gear{}
+
+// NewGear creates a Gear between two Objects. The angle of b times ratio, minus the angle of a, is kept at phase.
+func NewGear(a, b *Object, phase, ratio vect.Float, options DrawableOptions) *Gear {
+	gear := newGearConstraint(a.GetBody(), b.GetBody(), phase, ratio)
+	return &Gear{
+		baseConstraint: baseConstraint{constraint: gear, options: options},
+		gear:           gear,
+	}
+}
+
+// Draw draws the Gear on the provided imdraw.IMDraw as a wheel on each body, sized by the gear ratio,
+// connected by a line
+func (g Gear) Draw(imd *imdraw.IMDraw) {
+	g.drawPosed(imd, currentState)
+}
+
+// drawPosed draws the Gear with its bodies at the position and angle returned by pose
+func (g Gear) drawPosed(imd *imdraw.IMDraw, pose poseFunc) {
+	if g.options.JointOptions.Draw {
+		bodyA, bodyB := g.bodies()
+		poseA, poseB := pose(bodyA), pose(bodyB)
+		centreA, centreB := toPixel(poseA.position), toPixel(poseB.position)
+		radiusB := rotaryRadius * float64(absFloat(g.gear.ratio))
+
+		imd.Color = g.options.Color
+		drawLine(imd, centreA, centreB, g.options.Thickness)
+
+		imd.Push(centreA)
+		imd.Circle(rotaryRadius, max(g.options.Thickness, 1))
+		drawSpoke(imd, centreA, rotaryRadius, float64(poseA.angle), g.options.Thickness)
+
+		imd.Push(centreB)
+		imd.Circle(radiusB, max(g.options.Thickness, 1))
+		drawSpoke(imd, centreB, radiusB, float64(poseB.angle), g.options.Thickness)
+	}
+}
+
+type gearConstraint struct {
+	chipmunk.BasicConstraint
+	phase, ratio, ratioInv vect.Float
+
+	iSum, bias vect.Float
+	jAcc, jMax vect.Float
+}
+
+func newGearConstraint(a, b *chipmunk.Body, phase, ratio vect.Float) *gearConstraint {
+	return &gearConstraint{
+		BasicConstraint: chipmunk.NewConstraint(a, b),
+		phase:           phase,
+		ratio:           ratio,
+		ratioInv:        1 / ratio,
+	}
+}
+
+func (c *gearConstraint) PreStep(dt vect.Float) {
+	a, b := c.BodyA, c.BodyB
+
+	c.iSum = 1 / (invMoment(a)*c.ratioInv + c.ratio*invMoment(b))
+	c.bias = clampFloat(-biasCoef(c.ErrorBias, dt)*(b.Angle()*c.ratio-a.Angle()-c.phase)/dt, -c.MaxBias, c.MaxBias)
+	c.jMax = c.MaxForce * dt
+}
+
+func (c *gearConstraint) ApplyCachedImpulse(dtCoef vect.Float) {
+	c.apply(c.jAcc * dtCoef)
+}
+
+func (c *gearConstraint) ApplyImpulse() {
+	wr := angularVelocity(c.BodyB)*c.ratio - angularVelocity(c.BodyA)
+	j := (c.bias - wr) * c.iSum
+	jOld := c.jAcc
+	c.jAcc = clampFloat(jOld+j, -c.jMax, c.jMax)
+
+	c.apply(c.jAcc - jOld)
+}
+
+// apply applies angular impulse j to both bodies, scaled by the gear ratio
+func (c *gearConstraint) apply(j vect.Float) {
+	a, b := c.BodyA, c.BodyB
+	a.AddAngularVelocity(float32(-j * invMoment(a) * c.ratioInv))
+	b.AddAngularVelocity(float32(j * invMoment(b)))
+}
+
+func (c *gearConstraint) Impulse() vect.Float {
+	return absFloat(c.jAcc)
+}
@@ -0,0 +1,70 @@
+package pixelmunk
+
+import (
+	"github.com/gopxl/pixel/v2"
+	"github.com/vova616/chipmunk"
+	"github.com/vova616/chipmunk/transform"
+	"github.com/vova616/chipmunk/vect"
+)
+
+// The geometry helpers below transform a shape using the current position and angle of its body. chipmunk only
+// updates a shape's cached geometry (BB, TVerts, Tc, Ta/Tb) during Space.Step, so the cached values lag behind
+// when a body is moved with SetPosition or SetAngle, and are unset for bodies that were never added to a Space.
+
+// bodyTransform returns the current transform of the body
+func bodyTransform(body *chipmunk.Body) transform.Transform {
+	return transform.NewTransform(body.Position(), body.Angle())
+}
+
+// toWorld converts a point, relative to the body, into world coordinates
+func toWorld(body *chipmunk.Body, v vect.Vect) vect.Vect {
+	xf := bodyTransform(body)
+	return xf.TransformVect(v)
+}
+
+// toBody converts a point in world coordinates into a point relative to the body
+func toBody(body *chipmunk.Body, v vect.Vect) vect.Vect {
+	xf := bodyTransform(body)
+	return xf.RotateVectInv(vect.Sub(v, xf.Position))
+}
+
+// worldVertices returns the vertices of a box or polygon shape in world coordinates
+func worldVertices(shape *chipmunk.Shape) []vect.Vect {
+	var vertices chipmunk.Vertices
+	switch shape.ShapeType() {
+	case chipmunk.ShapeType_Box:
+		vertices = shape.GetAsBox().Polygon.Verts
+	case chipmunk.ShapeType_Polygon:
+		vertices = shape.GetAsPolygon().Verts
+	}
+
+	xf := bodyTransform(shape.Body)
+	result := make([]vect.Vect, len(vertices))
+	for i, v := range vertices {
+		result[i] = xf.TransformVect(v)
+	}
+	return result
+}
+
+// worldCircle returns the centre, in world coordinates, and the radius of a circle shape
+func worldCircle(shape *chipmunk.Shape) (vect.Vect, vect.Float) {
+	circle := shape.GetAsCircle()
+	return toWorld(shape.Body, circle.Position), circle.Radius
+}
+
+// worldSegment returns the endpoints, in world coordinates, and the radius of a segment shape
+func worldSegment(shape *chipmunk.Shape) (vect.Vect, vect.Vect, vect.Float) {
+	segment := shape.GetAsSegment()
+	xf := bodyTransform(shape.Body)
+	return xf.TransformVect(segment.A), xf.TransformVect(segment.B), segment.Radius
+}
+
+// toPixel converts a chipmunk vector to a pixel vector
+func toPixel(v vect.Vect) pixel.Vec {
+	return pixel.V(float64(v.X), float64(v.Y))
+}
+
+// toVect converts a pixel vector to a chipmunk vector
+func toVect(v pixel.Vec) vect.Vect {
+	return vect.Vect{X: vect.Float(v.X), Y: vect.Float(v.Y)}
+}
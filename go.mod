@@ -6,6 +6,7 @@ require (
 	github.com/gopxl/pixel/v2 v2.3.0
 	github.com/vova616/chipmunk v0.0.0-20180914035118-c3710bbc8933
 	golang.org/x/image v0.24.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -0,0 +1,130 @@
+package pixelmunk
+
+import (
+	"github.com/gopxl/pixel/v2/ext/imdraw"
+	"github.com/vova616/chipmunk"
+	"github.com/vova616/chipmunk/vect"
+)
+
+// GrooveJoint lets the anchor point of the second body slide along a groove on the first body, like a pivot on a rail
+type GrooveJoint struct {
+	baseConstraint
+	groove *grooveConstraint
+}
+
+var _ Drawable = &GrooveJoint{}
+
+// NewGrooveJoint creates a GrooveJoint between two Objects. grooveA and grooveB are the endpoints of the groove,
+// relative to Object a. anchorB is relative to Object b.
+func NewGrooveJoint(a, b *Object, grooveA, grooveB, anchorB vect.Vect, options DrawableOptions) *GrooveJoint {
+	groove := newGrooveConstraint(a.GetBody(), b.GetBody(), grooveA, grooveB, anchorB)
+	return &GrooveJoint{
+		baseConstraint: baseConstraint{constraint: groove, options: options},
+		groove:         groove,
+	}
+}
+
+// Draw draws the GrooveJoint on the provided imdraw.IMDraw as the groove, with the second body's anchor on it
+func (j GrooveJoint) Draw(imd *imdraw.IMDraw) {
+	j.drawPosed(imd, currentState)
+}
+
+// drawPosed draws the GrooveJoint with its bodies at the position and angle returned by pose
+func (j GrooveJoint) drawPosed(imd *imdraw.IMDraw, pose poseFunc) {
+	if j.options.JointOptions.Draw {
+		bodyA, bodyB := j.bodies()
+		poseA, poseB := pose(bodyA), pose(bodyB)
+
+		imd.Color = j.options.Color
+		drawLine(imd, toPixel(poseA.toWorld(j.groove.grooveA)), toPixel(poseA.toWorld(j.groove.grooveB)), j.options.Thickness)
+
+		imd.Push(toPixel(poseB.toWorld(j.groove.anchorB)))
+		imd.Circle(anchorRadius, 1)
+	}
+}
+
+type grooveConstraint struct {
+	chipmunk.BasicConstraint
+	grooveA, grooveB, grooveN vect.Vect
+	anchorB                   vect.Vect
+
+	grooveTn vect.Vect
+	clamp    vect.Float
+	r1, r2   vect.Vect
+	k1, k2   vect.Vect
+	jAcc     vect.Vect
+	jMaxLen  vect.Float
+	bias     vect.Vect
+}
+
+func newGrooveConstraint(a, b *chipmunk.Body, grooveA, grooveB, anchorB vect.Vect) *grooveConstraint {
+	return &grooveConstraint{
+		BasicConstraint: chipmunk.NewConstraint(a, b),
+		grooveA:         grooveA,
+		grooveB:         grooveB,
+		grooveN:         vect.Perp(vect.Normalize(vect.Sub(grooveB, grooveA))),
+		anchorB:         anchorB,
+	}
+}
+
+func (c *grooveConstraint) PreStep(dt vect.Float) {
+	a, b := c.BodyA, c.BodyB
+
+	// endpoints and axis of the groove, in world coordinates
+	ta := toWorld(a, c.grooveA)
+	tb := toWorld(a, c.grooveB)
+	n := rotate(a, c.grooveN)
+	d := vect.Dot(ta, n)
+
+	c.grooveTn = n
+	c.r2 = rotate(b, c.anchorB)
+
+	// clamp the anchor to the ends of the groove
+	td := vect.Cross(vect.Add(b.Position(), c.r2), n)
+	switch {
+	case td <= vect.Cross(ta, n):
+		c.clamp = 1
+		c.r1 = vect.Sub(ta, a.Position())
+	case td >= vect.Cross(tb, n):
+		c.clamp = -1
+		c.r1 = vect.Sub(tb, a.Position())
+	default:
+		c.clamp = 0
+		c.r1 = vect.Sub(vect.Add(vect.Mult(vect.Perp(n), -td), vect.Mult(n, d)), a.Position())
+	}
+
+	c.k1, c.k2 = kTensor(a, b, c.r1, c.r2)
+	c.jMaxLen = c.MaxForce * dt
+
+	delta := vect.Sub(vect.Add(b.Position(), c.r2), vect.Add(a.Position(), c.r1))
+	c.bias = vect.Clamp(vect.Mult(delta, -biasCoef(c.ErrorBias, dt)/dt), c.MaxBias)
+}
+
+func (c *grooveConstraint) ApplyCachedImpulse(dtCoef vect.Float) {
+	applyImpulses(c.BodyA, c.BodyB, c.r1, c.r2, vect.Mult(c.jAcc, dtCoef))
+}
+
+// constrain limits impulse j: at the ends of the groove, the anchor may only be pushed back into the groove
+func (c *grooveConstraint) constrain(j vect.Vect) vect.Vect {
+	n := c.grooveTn
+	if c.clamp*vect.Cross(j, n) <= 0 {
+		j = vect.Mult(n, vect.Dot(j, n)/vect.Dot(n, n))
+	}
+	return vect.Clamp(j, c.jMaxLen)
+}
+
+func (c *grooveConstraint) ApplyImpulse() {
+	a, b := c.BodyA, c.BodyB
+
+	vr := relativeVelocity(a, b, c.r1, c.r2)
+	j := multK(vect.Sub(c.bias, vr), c.k1, c.k2)
+	jOld := c.jAcc
+	c.jAcc = c.constrain(vect.Add(jOld, j))
+	j = vect.Sub(c.jAcc, jOld)
+
+	applyImpulses(a, b, c.r1, c.r2, j)
+}
+
+func (c *grooveConstraint) Impulse() vect.Float {
+	return vect.Length(c.jAcc)
+}
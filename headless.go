@@ -0,0 +1,22 @@
+package pixelmunk
+
+import "context"
+
+// RunHeadless runs the world simulation without opening a window. Each step advances the World by 1/PhysicsHz seconds,
+// as fast as possible. If steps is zero or negative, RunHeadless runs until the context is cancelled or, when replaying,
+// until the end of the replay. It returns an error wrapping ErrReplayDiverged if the replay didn't follow the recording.
+//
+// Only the StepCallback is called: RunFunc and RunCallback require a window and are ignored.
+func (w *World) RunHeadless(ctx context.Context, steps int) error {
+	dt := w.physicsDt()
+	replaying := w.Replaying()
+	for step := 0; ; step++ {
+		if steps > 0 && step >= steps || steps <= 0 && replaying && !w.Replaying() {
+			return w.ReplayErr()
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		w.Step(dt)
+	}
+}
@@ -0,0 +1,77 @@
+package pixelmunk
+
+import (
+	"context"
+	"errors"
+	"github.com/vova616/chipmunk/vect"
+	"testing"
+)
+
+func TestWorld_RunHeadless(t *testing.T) {
+	tests := []struct {
+		name      string
+		steps     int
+		cancelAt  int
+		wantSteps int
+		wantErr   error
+	}{
+		{name: "single step", steps: 1, wantSteps: 1},
+		{name: "fixed steps", steps: 60, wantSteps: 60},
+		{name: "cancelled", steps: 60, cancelAt: 10, wantSteps: 10, wantErr: context.Canceled},
+		{name: "until cancelled", steps: 0, cancelAt: 25, wantSteps: 25, wantErr: context.Canceled},
+		{name: "negative steps run until cancelled", steps: -1, cancelAt: 5, wantSteps: 5, wantErr: context.Canceled},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			w := NewWorld("test", 0, 0, 100, 100)
+			w.Space.Gravity = vect.Vect{Y: -900}
+			ball := NewCircle(DrawableOptions{BodyOptions: BodyOptions{
+				Position:      vect.Vect{X: 50, Y: 100},
+				Mass:          1,
+				CircleOptions: CircleOptions{Radius: 5},
+			}})
+			w.Add(ball)
+
+			var steps int
+			w.StepCallback = func(dt vect.Float) {
+				if dt != 1/vect.Float(w.FrameRate) {
+					t.Errorf("dt: got %v, want %v", dt, 1/vect.Float(w.FrameRate))
+				}
+				steps++
+				if steps == tt.cancelAt {
+					cancel()
+				}
+			}
+
+			if err := w.RunHeadless(ctx, tt.steps); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("RunHeadless: got %v, want %v", err, tt.wantErr)
+			}
+			if steps != tt.wantSteps {
+				t.Errorf("steps: got %d, want %d", steps, tt.wantSteps)
+			}
+			// chipmunk moves bodies before it applies gravity, so the ball starts falling in the second step
+			if y := ball.GetBody().Position().Y; tt.wantSteps > 1 && y >= 100 {
+				t.Errorf("ball didn't fall: y = %v", y)
+			}
+		})
+	}
+}
+
+func TestWorld_RunHeadless_cancelledBeforeStart(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	w := NewWorld("test", 0, 0, 100, 100)
+	var steps int
+	w.StepCallback = func(vect.Float) { steps++ }
+
+	if err := w.RunHeadless(ctx, 10); !errors.Is(err, context.Canceled) {
+		t.Fatalf("RunHeadless: got %v, want %v", err, context.Canceled)
+	}
+	if steps != 0 {
+		t.Errorf("steps: got %d, want 0", steps)
+	}
+}
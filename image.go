@@ -0,0 +1,150 @@
+package pixelmunk
+
+import (
+	"github.com/gopxl/pixel/v2"
+	"golang.org/x/image/colornames"
+	"image"
+	"image/draw"
+	"math"
+)
+
+// Image renders the World into a new image of the given size. The image is cleared to black before drawing,
+// like the window in the default run loop.
+func (w *World) Image(width, height int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), image.NewUniform(colornames.Black), image.Point{}, draw.Src)
+	w.DrawImage(img)
+	return img
+}
+
+// DrawImage renders all Objects in the World onto the provided image, using a software rasteriser.
+// The World's Bounds are scaled to cover the whole image. No window or GPU is needed.
+func (w *World) DrawImage(img *image.RGBA) {
+	size := img.Bounds().Size()
+	w.Draw(&imageTarget{
+		img: img,
+		matrix: pixel.IM.
+			Moved(w.Bounds.Min.Scaled(-1)).
+			ScaledXY(pixel.ZV, pixel.V(float64(size.X)/w.Bounds.W(), float64(size.Y)/w.Bounds.H())),
+	})
+}
+
+// imageTarget is a pixel.Target that rasterises triangles into an image.RGBA
+type imageTarget struct {
+	img    *image.RGBA
+	matrix pixel.Matrix
+}
+
+var _ pixel.Target = &imageTarget{}
+
+// MakeTriangles returns a copy of the provided triangles that draws onto the image
+func (t *imageTarget) MakeTriangles(tri pixel.Triangles) pixel.TargetTriangles {
+	return &imageTriangles{Triangles: tri.Copy(), dst: t}
+}
+
+// MakePicture is not supported: the World only draws untextured shapes
+func (t *imageTarget) MakePicture(pixel.Picture) pixel.TargetPicture {
+	panic("imageTarget: pictures are not supported")
+}
+
+type imageTriangles struct {
+	pixel.Triangles
+	dst *imageTarget
+}
+
+// Draw rasterises the triangles onto the image
+func (t *imageTriangles) Draw() {
+	positions, ok := t.Triangles.(pixel.TrianglesPosition)
+	if !ok {
+		return
+	}
+	colors, _ := t.Triangles.(pixel.TrianglesColor)
+
+	for i := 0; i+2 < t.Len(); i += 3 {
+		var v [3]vertex
+		for j := range v {
+			v[j].pos = t.dst.matrix.Project(positions.Position(i + j))
+			v[j].color = pixel.Alpha(1)
+			if colors != nil {
+				v[j].color = colors.Color(i + j)
+			}
+		}
+		t.dst.fillTriangle(v)
+	}
+}
+
+type vertex struct {
+	pos   pixel.Vec
+	color pixel.RGBA
+}
+
+// fillTriangle draws a single triangle, interpolating the vertex colors. Pixels are sampled at their centre;
+// pixels on a shared edge are only drawn once (top-left rule), so translucent shapes don't show seams.
+func (t *imageTarget) fillTriangle(v [3]vertex) {
+	area := edge(v[0].pos, v[1].pos, v[2].pos)
+	if area == 0 {
+		return
+	}
+	if area < 0 {
+		v[1], v[2] = v[2], v[1]
+		area = -area
+	}
+
+	bounds := t.img.Bounds()
+	height := bounds.Dy()
+	minX := clampInt(int(math.Floor(math.Min(v[0].pos.X, math.Min(v[1].pos.X, v[2].pos.X)))), 0, bounds.Dx()-1)
+	maxX := clampInt(int(math.Ceil(math.Max(v[0].pos.X, math.Max(v[1].pos.X, v[2].pos.X)))), 0, bounds.Dx()-1)
+	minY := clampInt(int(math.Floor(math.Min(v[0].pos.Y, math.Min(v[1].pos.Y, v[2].pos.Y)))), 0, height-1)
+	maxY := clampInt(int(math.Ceil(math.Max(v[0].pos.Y, math.Max(v[1].pos.Y, v[2].pos.Y)))), 0, height-1)
+
+	for y := minY; y <= maxY; y++ {
+		for x := minX; x <= maxX; x++ {
+			p := pixel.V(float64(x)+0.5, float64(y)+0.5)
+			w0 := edge(v[1].pos, v[2].pos, p)
+			w1 := edge(v[2].pos, v[0].pos, p)
+			w2 := edge(v[0].pos, v[1].pos, p)
+			if !inside(w0, v[1].pos, v[2].pos) || !inside(w1, v[2].pos, v[0].pos) || !inside(w2, v[0].pos, v[1].pos) {
+				continue
+			}
+			c := v[0].color.Scaled(w0 / area).
+				Add(v[1].color.Scaled(w1 / area)).
+				Add(v[2].color.Scaled(w2 / area))
+			// image rows run top to bottom, pixel's y-axis points up
+			t.blend(bounds.Min.X+x, bounds.Min.Y+height-1-y, c)
+		}
+	}
+}
+
+// blend draws the alpha-premultiplied color c over the pixel at (x, y)
+func (t *imageTarget) blend(x, y int, c pixel.RGBA) {
+	offset := t.img.PixOffset(x, y)
+	pix := t.img.Pix[offset : offset+4 : offset+4]
+	a := clamp01(c.A)
+	for i, src := range []float64{c.R, c.G, c.B, c.A} {
+		dst := float64(pix[i]) / 0xff
+		pix[i] = uint8(math.Round(clamp01(clamp01(src)+dst*(1-a)) * 0xff))
+	}
+}
+
+// edge returns twice the signed area of the triangle (a, b, p): positive if p lies to the left of a->b
+func edge(a, b, p pixel.Vec) float64 {
+	return (b.X-a.X)*(p.Y-a.Y) - (b.Y-a.Y)*(p.X-a.X)
+}
+
+// inside reports whether a point with edge value w lies inside the (counter-clockwise) edge a->b.
+// Points exactly on the edge are only inside for left and top edges.
+func inside(w float64, a, b pixel.Vec) bool {
+	if w != 0 {
+		return w > 0
+	}
+	dx, dy := b.X-a.X, b.Y-a.Y
+	return dy < 0 || (dy == 0 && dx < 0)
+}
+
+func clampInt(v, low, high int) int {
+	return max(low, min(v, high))
+}
+
+func clamp01(v float64) float64 {
+	return math.Max(0, math.Min(v, 1))
+}
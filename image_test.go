@@ -0,0 +1,145 @@
+package pixelmunk
+
+import (
+	"bytes"
+	"flag"
+	"github.com/vova616/chipmunk/vect"
+	"golang.org/x/image/colornames"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update the golden files in testdata")
+
+func TestWorld_Image(t *testing.T) {
+	tests := []struct {
+		name    string
+		objects func() []Drawable
+	}{
+		{
+			name:    "empty",
+			objects: func() []Drawable { return nil },
+		},
+		{
+			name: "circle",
+			objects: func() []Drawable {
+				return []Drawable{NewCircle(DrawableOptions{
+					Color: colornames.Red,
+					BodyOptions: BodyOptions{
+						Position:      vect.Vect{X: 50, Y: 50},
+						Mass:          1,
+						CircleOptions: CircleOptions{Radius: 20},
+					},
+				})}
+			},
+		},
+		{
+			name: "boxes",
+			objects: func() []Drawable {
+				return []Drawable{
+					NewBox(DrawableOptions{
+						Color: colornames.Green,
+						BodyOptions: BodyOptions{
+							StaticBody: true,
+							Position:   vect.Vect{X: 50, Y: 10},
+							BoxOptions: BoxOptions{Width: 90, Height: 10},
+						},
+					}),
+					NewBox(DrawableOptions{
+						Color: colornames.Blue,
+						BodyOptions: BodyOptions{
+							Position:   vect.Vect{X: 50, Y: 60},
+							Angle:      0.5,
+							Mass:       1,
+							BoxOptions: BoxOptions{Width: 30, Height: 20},
+						},
+					}),
+				}
+			},
+		},
+		{
+			name: "segment",
+			objects: func() []Drawable {
+				return []Drawable{NewSegment(DrawableOptions{
+					Color: colornames.Yellow,
+					BodyOptions: BodyOptions{
+						StaticBody: true,
+						SegmentOptions: SegmentOptions{
+							A:      vect.Vect{X: 10, Y: 20},
+							B:      vect.Vect{X: 90, Y: 80},
+							Radius: 3,
+						},
+					},
+				})}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := NewWorld("test", 0, 0, 100, 100)
+			w.Add(tt.objects()...)
+			checkGolden(t, w.Image(200, 200))
+		})
+	}
+}
+
+func TestWorld_DrawImage(t *testing.T) {
+	w := NewWorld("test", -50, -50, 50, 50)
+	w.Add(NewCircle(DrawableOptions{
+		Color: colornames.White,
+		BodyOptions: BodyOptions{
+			Mass:          1,
+			CircleOptions: CircleOptions{Radius: 25},
+		},
+	}))
+
+	// DrawImage draws over the image instead of clearing it, and scales the Bounds to the size of the image
+	img := image.NewRGBA(image.Rect(0, 0, 300, 150))
+	draw.Draw(img, img.Bounds(), image.NewUniform(colornames.Navy), image.Point{}, draw.Src)
+	w.DrawImage(img)
+	checkGolden(t, img)
+}
+
+// checkGolden compares the image with the PNG in testdata that has the name of the test. Run the tests with -update
+// to write the golden files.
+func checkGolden(t *testing.T, img *image.RGBA) {
+	t.Helper()
+	filename := filepath.Join("testdata", filepath.FromSlash(t.Name())+".png")
+	if *update {
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, img); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.MkdirAll(filepath.Dir(filename), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filename, buf.Bytes(), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	f, err := os.Open(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = f.Close() }()
+	golden, err := png.Decode(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if golden.Bounds() != img.Bounds() {
+		t.Fatalf("size: got %v, want %v", img.Bounds(), golden.Bounds())
+	}
+	for y := img.Bounds().Min.Y; y < img.Bounds().Max.Y; y++ {
+		for x := img.Bounds().Min.X; x < img.Bounds().Max.X; x++ {
+			if got, want := img.RGBAAt(x, y), color.RGBAModel.Convert(golden.At(x, y)); got != want {
+				t.Fatalf("pixel (%d, %d): got %v, want %v", x, y, got, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package pixelmunk
+
+import (
+	"github.com/gopxl/pixel/v2"
+	"github.com/vova616/chipmunk/vect"
+)
+
+// InputEvent is the change in the state of a key or mouse button that triggers a key binding
+type InputEvent int
+
+const (
+	// InputPressed triggers in the frame in which the button is pressed
+	InputPressed InputEvent = iota
+	// InputReleased triggers in the frame in which the button is released
+	InputReleased
+	// InputHeld triggers in every frame in which the button is down
+	InputHeld
+	// InputRepeated triggers when the button is pressed and when the keyboard repeats the key while it's held down
+	InputRepeated
+)
+
+// input is the part of opengl.Window that the default run loop reads the state of the keyboard and mouse from
+type input interface {
+	Pressed(pixel.Button) bool
+	JustPressed(pixel.Button) bool
+	JustReleased(pixel.Button) bool
+	Repeated(pixel.Button) bool
+	MousePosition() pixel.Vec
+	MouseScroll() pixel.Vec
+}
+
+type keyBinding struct {
+	button pixel.Button
+	event  InputEvent
+	action func(*World)
+}
+
+// BindKey calls the action in every frame in which the event occurs for the button. The button can be a key or
+// a mouse button. Actions are called before the World is stepped, in the order in which they were bound.
+// Key bindings are only supported by the default run loop.
+func (w *World) BindKey(button pixel.Button, event InputEvent, action func(*World)) {
+	w.keyBindings = append(w.keyBindings, keyBinding{button: button, event: event, action: action})
+}
+
+// BindScroll calls the action in every frame in which the mouse wheel is scrolled, with the scroll offset.
+// Scroll bindings are only supported by the default run loop.
+func (w *World) BindScroll(action func(w *World, scroll pixel.Vec)) {
+	w.scrollBindings = append(w.scrollBindings, action)
+}
+
+// MousePosition returns the position of the mouse, in world coordinates, at the start of the current frame.
+// The mouse position is only tracked by the default run loop.
+func (w *World) MousePosition() vect.Vect {
+	return w.mousePosition
+}
+
+// handleInput calls the actions bound to the keyboard and mouse events of the current frame. While replaying, the
+// bindings are called from the recording instead, before the step in which they were recorded.
+func (w *World) handleInput(in input) {
+	w.mousePosition = w.ScreenToWorld(in.MousePosition())
+	w.rewinding = false
+	if !w.DisableDebugKeys {
+		for _, binding := range debugKeys {
+			if binding.triggered(in) {
+				binding.action(w)
+			}
+		}
+	}
+	if w.Replaying() {
+		return
+	}
+	for i, binding := range w.keyBindings {
+		if binding.triggered(in) {
+			w.recordInput(replayInput{Binding: &i})
+			binding.action(w)
+		}
+	}
+	if scroll := in.MouseScroll(); scroll != (pixel.Vec{}) && len(w.scrollBindings) > 0 {
+		offset := toSceneVec(toVect(scroll))
+		w.recordInput(replayInput{Scroll: &offset})
+		for _, action := range w.scrollBindings {
+			action(w, scroll)
+		}
+	}
+}
+
+func (b keyBinding) triggered(in input) bool {
+	switch b.event {
+	case InputPressed:
+		return in.JustPressed(b.button)
+	case InputReleased:
+		return in.JustReleased(b.button)
+	case InputHeld:
+		return in.Pressed(b.button)
+	case InputRepeated:
+		return in.JustPressed(b.button) || in.Repeated(b.button)
+	default:
+		return false
+	}
+}
@@ -0,0 +1,33 @@
+package pixelmunk
+
+import (
+	"github.com/gopxl/pixel/v2"
+	"testing"
+)
+
+func TestWorld_handleInput_debugKeys(t *testing.T) {
+	tests := []struct {
+		name             string
+		disableDebugKeys bool
+		wantPaused       bool
+	}{
+		{name: "on by default", wantPaused: true},
+		{name: "disabled", disableDebugKeys: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := NewWorld("test", 0, 0, 100, 100)
+			var bound bool
+			w.BindKey(pixel.KeyP, InputPressed, func(*World) { bound = true })
+			w.DisableDebugKeys = tt.disableDebugKeys
+
+			w.handleInput(fakeInput{pressed: map[pixel.Button]bool{pixel.KeyP: true}})
+			if w.Paused() != tt.wantPaused {
+				t.Errorf("Paused: got %v, want %v", w.Paused(), tt.wantPaused)
+			}
+			if !bound {
+				t.Error("the World's own binding wasn't called")
+			}
+		})
+	}
+}
@@ -1,11 +1,9 @@
 package pixelmunk
 
 import (
-	"github.com/gopxl/pixel/v2"
 	"github.com/gopxl/pixel/v2/ext/imdraw"
 	"github.com/vova616/chipmunk"
 	"github.com/vova616/chipmunk/vect"
-	"math"
 )
 
 // Joint joins two bodies together
@@ -20,6 +18,13 @@ type Joint struct {
 // JointOptions contains options for drawing joints
 type JointOptions struct {
 	Draw bool
+	// Coils is the number of coils drawn for a Spring. Defaults to 8.
+	Coils int
+	// BreakImpulse breaks the joint: if the impulse that the joint applies during a single step (or substep, see
+	// World.Substeps) exceeds BreakImpulse, the World removes the joint and calls World.OnJointBroken. To break at
+	// a force, rather than an impulse, multiply the force by the duration of a (sub)step. Zero means the joint
+	// never breaks.
+	BreakImpulse vect.Float
 }
 
 var _ Drawable = &Joint{}
@@ -55,7 +60,12 @@ func (j Joint) GetBody() *chipmunk.Body {
 	return nil
 }
 
-// GetJoint returns the chipmunk.PivotJoint that the Object represents
+// GetConstraint returns the chipmunk.Constraint that the Joint represents
+func (j Joint) GetConstraint() chipmunk.Constraint {
+	return j.pivotJoint
+}
+
+// GetJoint returns the chipmunk.PivotJoint that the Joint represents
 func (j Joint) GetJoint() *chipmunk.PivotJoint {
 	return j.pivotJoint
 }
@@ -67,36 +77,29 @@ func (j Joint) GetOptions() DrawableOptions {
 
 // Draw draws the Joint on the provided imdraw.IMDraw
 func (j Joint) Draw(imd *imdraw.IMDraw) {
+	j.drawPosed(imd, currentState)
+}
+
+// drawPosed draws the Joint with its bodies at the position and angle returned by pose
+func (j Joint) drawPosed(imd *imdraw.IMDraw, pose poseFunc) {
 	if j.options.JointOptions.Draw {
-		pA := j.pivotJoint.BodyA.Position()
-		pB := j.pivotJoint.BodyB.Position()
+		bodyA, bodyB := j.pivotJoint.BodyA, j.pivotJoint.BodyB
+		poseA, poseB := pose(bodyA), pose(bodyB)
+		pA, pB := toPixel(poseA.position), toPixel(poseB.position)
+		anchorA, anchorB := toPixel(poseA.toWorld(j.origOffsetA)), toPixel(poseB.toWorld(j.origOffsetB))
 
 		imd.Color = j.options.Color
 
 		// line from first body to offset
-		imd.Push(pixel.V(float64(pA.X), float64(pA.Y)))
-		pA.Add(rotateVector(j.origOffsetA, j.pivotJoint.BodyA.Angle()))
-		imd.Push(pixel.V(float64(pA.X), float64(pA.Y)))
+		imd.Push(pA, anchorA)
 		imd.Line(j.options.Thickness)
 
 		// Line from 2nd body to offset
-		imd.Push(pixel.V(float64(pB.X), float64(pB.Y)))
-		pB.Add(rotateVector(j.origOffsetB, j.pivotJoint.BodyB.Angle()))
-		imd.Push(pixel.V(float64(pB.X), float64(pB.Y)))
+		imd.Push(pB, anchorB)
 		imd.Line(j.options.Thickness)
 
 		// line between 2 offsets
-		imd.Push(pixel.V(float64(pA.X), float64(pA.Y)), pixel.V(float64(pB.X), float64(pB.Y)))
+		imd.Push(anchorA, anchorB)
 		imd.Line(j.options.Thickness)
 	}
 }
-
-func rotateVector(v vect.Vect, angle vect.Float) (a vect.Vect) {
-	//𝑥2=cos𝛽𝑥1−sin𝛽𝑦1
-	//𝑦2=sin𝛽𝑥1+cos𝛽𝑦1
-	a = vect.Vect{
-		X: v.X*vect.Float(math.Cos(float64(angle))) - v.Y*vect.Float(math.Sin(float64(angle))),
-		Y: v.X*vect.Float(math.Sin(float64(angle))) + v.Y*vect.Float(math.Cos(float64(angle))),
-	}
-	return
-}
@@ -0,0 +1,111 @@
+package pixelmunk
+
+import (
+	"github.com/gopxl/pixel/v2/ext/imdraw"
+	"github.com/vova616/chipmunk"
+	"github.com/vova616/chipmunk/vect"
+	"math"
+)
+
+// Motor keeps the relative angular velocity of two bodies at a constant rate
+type Motor struct {
+	baseConstraint
+	motor *motorConstraint
+}
+
+var _ Drawable = &Motor{}
+
+// NewMotor creates a Motor between two Objects. The angular velocity of a, minus that of b, is kept at rate
+// (in radians per second). The torque of the motor is unlimited, unless set with SetMaxForce.
+func NewMotor(a, b *Object, rate vect.Float, options DrawableOptions) *Motor {
+	motor := newMotorConstraint(a.GetBody(), b.GetBody(), rate)
+	return &Motor{
+		baseConstraint: baseConstraint{constraint: motor, options: options},
+		motor:          motor,
+	}
+}
+
+// Rate returns the relative angular velocity that the Motor drives the bodies at
+func (m Motor) Rate() vect.Float {
+	return m.motor.rate
+}
+
+// SetRate changes the relative angular velocity that the Motor drives the bodies at. SetRate can be called while
+// the World is running, e.g. from World.RunCallback.
+func (m Motor) SetRate(rate vect.Float) {
+	m.motor.rate = rate
+}
+
+// MaxForce returns the maximum torque that the Motor can apply
+func (m Motor) MaxForce() vect.Float {
+	return m.motor.MaxForce
+}
+
+// SetMaxForce sets the maximum torque that the Motor can apply. A Motor with a rate of zero and a low maximum force
+// acts as a brake. SetMaxForce can be called while the World is running, e.g. from World.RunCallback.
+func (m Motor) SetMaxForce(maxForce vect.Float) {
+	m.motor.MaxForce = maxForce
+}
+
+// Draw draws the Motor on the provided imdraw.IMDraw as an arc around the second body, in the direction in which
+// the motor turns it, with a spoke for the current angle of the second body
+func (m Motor) Draw(imd *imdraw.IMDraw) {
+	m.drawPosed(imd, currentState)
+}
+
+// drawPosed draws the Motor with its bodies at the position and angle returned by pose
+func (m Motor) drawPosed(imd *imdraw.IMDraw, pose poseFunc) {
+	if m.options.JointOptions.Draw {
+		_, bodyB := m.bodies()
+		poseB := pose(bodyB)
+		centre := toPixel(poseB.position)
+		angle := float64(poseB.angle)
+
+		imd.Color = m.options.Color
+		if m.motor.rate != 0 {
+			drawArc(imd, centre, rotaryRadius, angle, angle-math.Copysign(1.5*math.Pi, float64(m.motor.rate)), m.options.Thickness)
+		}
+		drawSpoke(imd, centre, rotaryRadius, angle, m.options.Thickness)
+	}
+}
+
+// motorConstraint drives the relative rate of two bodies. chipmunk.SimpleMotor doesn't scale MaxForce by the time step,
+// so it is reimplemented here.
+type motorConstraint struct {
+	chipmunk.BasicConstraint
+	rate vect.Float
+
+	iSum       vect.Float
+	jAcc, jMax vect.Float
+}
+
+func newMotorConstraint(a, b *chipmunk.Body, rate vect.Float) *motorConstraint {
+	return &motorConstraint{
+		BasicConstraint: chipmunk.NewConstraint(a, b),
+		rate:            rate,
+	}
+}
+
+func (c *motorConstraint) PreStep(dt vect.Float) {
+	c.iSum = 1 / (invMoment(c.BodyA) + invMoment(c.BodyB))
+	c.jMax = c.MaxForce * dt
+}
+
+func (c *motorConstraint) ApplyCachedImpulse(dtCoef vect.Float) {
+	applyAngularImpulses(c.BodyA, c.BodyB, c.jAcc*dtCoef)
+}
+
+func (c *motorConstraint) ApplyImpulse() {
+	a, b := c.BodyA, c.BodyB
+
+	wr := angularVelocity(b) - angularVelocity(a) + c.rate
+	j := -wr * c.iSum
+	jOld := c.jAcc
+	c.jAcc = clampFloat(jOld+j, -c.jMax, c.jMax)
+
+	applyAngularImpulses(a, b, c.jAcc-jOld)
+}
+
+func (c *motorConstraint) Impulse() vect.Float {
+	return absFloat(c.jAcc)
+}
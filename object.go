@@ -6,7 +6,6 @@ import (
 	"github.com/gopxl/pixel/v2/ext/imdraw"
 	"github.com/vova616/chipmunk"
 	"github.com/vova616/chipmunk/vect"
-	"golang.org/x/image/colornames"
 	"image/color"
 	"math"
 )
@@ -33,16 +32,29 @@ type CustomDrawFunc func(object *Object, draw *imdraw.IMDraw)
 
 // BodyOptions holds the physical attributes for the Object
 type BodyOptions struct {
-	StaticBody    bool
-	Position      vect.Vect
-	Angle         vect.Float
-	Mass          vect.Float
-	Velocity      vect.Vect
-	Elasticity    vect.Float
-	Friction      vect.Float
-	Type          chipmunk.ShapeType
-	CircleOptions CircleOptions
-	BoxOptions    BoxOptions
+	StaticBody     bool
+	Position       vect.Vect
+	Angle          vect.Float
+	Mass           vect.Float
+	Velocity       vect.Vect
+	Elasticity     vect.Float
+	Friction       vect.Float
+	Type           chipmunk.ShapeType
+	CircleOptions  CircleOptions
+	BoxOptions     BoxOptions
+	PolygonOptions PolygonOptions
+	SegmentOptions SegmentOptions
+	// Sensor objects report collisions (see World.OnCollisionBegin and World.Occupants), but don't push other objects
+	Sensor bool
+	// CollisionType identifies the kind of Object in collision callbacks. See CollisionFilter.
+	CollisionType CollisionType
+	// CollisionGroup: shapes in the same (non-zero) group never collide, e.g. the limbs of a ragdoll
+	CollisionGroup chipmunk.Group
+	// CollisionCategory is a bitmask of the categories that the Object belongs to. Zero means all categories.
+	CollisionCategory uint32
+	// CollisionMask is a bitmask of the categories that the Object collides with. Zero means all categories.
+	// Two Objects only collide if each Object's category matches the other Object's mask.
+	CollisionMask uint32
 }
 
 // CircleOptions holds the attributes for a Circle object
@@ -56,14 +68,36 @@ type BoxOptions struct {
 	Height vect.Float
 }
 
+// PolygonOptions holds the attributes for a Polygon object
+type PolygonOptions struct {
+	// Vertices of the polygon, relative to the Object's Position. The polygon must be convex.
+	Vertices []vect.Vect
+}
+
+// SegmentOptions holds the attributes for a Segment object
+type SegmentOptions struct {
+	// A and B are the endpoints of the segment, relative to the Object's Position
+	A, B   vect.Vect
+	Radius vect.Float
+}
+
 var _ Drawable = &Object{}
 
 // NewObject creates a new Object for the provided Body and DrawableOptions
 func NewObject(body *chipmunk.Body, options DrawableOptions) *Object {
-	return &Object{
+	o := &Object{
 		body:    body,
 		options: options,
 	}
+	if options.BodyOptions.CollisionGroup != 0 {
+		o.SetCollisionGroup(options.BodyOptions.CollisionGroup)
+	}
+	if options.BodyOptions.Sensor {
+		for _, shape := range body.Shapes {
+			shape.IsSensor = true
+		}
+	}
+	return o
 }
 
 // NewObjectWithShape creates a new Object for the provided Shape and DrawableOptions
@@ -75,7 +109,7 @@ func NewObjectWithShape(shape *chipmunk.Shape, options DrawableOptions) *Object
 	if options.BodyOptions.StaticBody {
 		body = chipmunk.NewBodyStatic()
 	} else {
-		body = chipmunk.NewBody(options.BodyOptions.Mass, shape.Moment(float32(options.BodyOptions.Mass)))
+		body = chipmunk.NewBody(options.BodyOptions.Mass, moment(shape, options.BodyOptions.Mass))
 	}
 	body.AddShape(shape)
 	body.SetPosition(options.BodyOptions.Position)
@@ -85,6 +119,34 @@ func NewObjectWithShape(shape *chipmunk.Shape, options DrawableOptions) *Object
 	return NewObject(body, options)
 }
 
+// moment returns the moment of inertia of a shape with the given mass, around the origin of its body.
+// chipmunk's moments ignore the offset of boxes and circles, and PolygonShape.Moment logs a warning on every call,
+// so these are calculated here.
+func moment(shape *chipmunk.Shape, mass vect.Float) vect.Float {
+	switch shape.ShapeType() {
+	case chipmunk.ShapeType_Circle:
+		circle := shape.GetAsCircle()
+		return mass * (circle.Radius*circle.Radius/2 + vect.LengthSqr(circle.Position))
+	case chipmunk.ShapeType_Box:
+		box := shape.GetAsBox()
+		return mass * ((box.Width*box.Width+box.Height*box.Height)/12 + vect.LengthSqr(box.Position))
+	case chipmunk.ShapeType_Polygon:
+		poly := shape.GetAsPolygon()
+		var sum1, sum2 vect.Float
+		for i := 0; i < poly.NumVerts; i++ {
+			v1 := poly.Verts[i]
+			v2 := poly.Verts[(i+1)%poly.NumVerts]
+
+			a := vect.Cross(v2, v1)
+			sum1 += a * (vect.Dot(v1, v1) + vect.Dot(v1, v2) + vect.Dot(v2, v2))
+			sum2 += a
+		}
+		return mass * sum1 / (6 * sum2)
+	default:
+		return shape.Moment(float32(mass))
+	}
+}
+
 // GetType returns the type of drawable
 func (o Object) GetType() DrawableType {
 	return DrawableBody
@@ -95,11 +157,56 @@ func (o Object) GetBody() *chipmunk.Body {
 	return o.body
 }
 
-// GetJoint returns the chipmunk.PivotJoint that the Object represents
-func (o Object) GetJoint() *chipmunk.PivotJoint {
+// GetConstraint returns nil: an Object is a body, not a constraint
+func (o Object) GetConstraint() chipmunk.Constraint {
 	return nil
 }
 
+// baseObject returns the Object. Types that embed an Object, like Terrain, inherit it, so the World can find the
+// Object of any Drawable built on one.
+func (o *Object) baseObject() *Object {
+	return o
+}
+
+// objectOf returns the Object of a Drawable, or nil if the Drawable isn't built on an Object
+func objectOf(drawable Drawable) *Object {
+	if o, ok := drawable.(interface{ baseObject() *Object }); ok {
+		return o.baseObject()
+	}
+	return nil
+}
+
+// SetCollisionGroup changes the collision group of all shapes of the Object
+func (o *Object) SetCollisionGroup(group chipmunk.Group) {
+	o.options.BodyOptions.CollisionGroup = group
+	for _, shape := range o.body.Shapes {
+		shape.Group = group
+	}
+}
+
+// SetCollisionCategory changes the categories that the Object belongs to. Zero means all categories.
+func (o *Object) SetCollisionCategory(category uint32) {
+	o.options.BodyOptions.CollisionCategory = category
+}
+
+// SetCollisionMask changes the categories that the Object collides with. Zero means all categories.
+func (o *Object) SetCollisionMask(mask uint32) {
+	o.options.BodyOptions.CollisionMask = mask
+}
+
+// collidesWith reports whether the categories and masks of the Objects allow them to collide
+func (o *Object) collidesWith(other *Object) bool {
+	return allIfZero(o.options.BodyOptions.CollisionCategory)&allIfZero(other.options.BodyOptions.CollisionMask) != 0 &&
+		allIfZero(other.options.BodyOptions.CollisionCategory)&allIfZero(o.options.BodyOptions.CollisionMask) != 0
+}
+
+func allIfZero(bits uint32) uint32 {
+	if bits == 0 {
+		return math.MaxUint32
+	}
+	return bits
+}
+
 // GetOptions returns the DrawableOptions that were used to create the Object
 func (o Object) GetOptions() DrawableOptions {
 	return o.options
@@ -108,73 +215,90 @@ func (o Object) GetOptions() DrawableOptions {
 // Draw draws the Object on the provided imdraw.IMDraw
 func (o Object) Draw(imd *imdraw.IMDraw) {
 	for _, shape := range o.GetBody().Shapes {
+		if shape.IsSensor {
+			o.drawSensor(imd, shape)
+			continue
+		}
 		switch shape.ShapeType() {
 		case chipmunk.ShapeType_Circle:
 			o.drawCircle(imd, shape)
-		case chipmunk.ShapeType_Box:
-			o.drawBox(imd, shape)
+		case chipmunk.ShapeType_Box, chipmunk.ShapeType_Polygon:
+			o.drawPolygon(imd, shape)
+		case chipmunk.ShapeType_Segment:
+			o.drawSegment(imd, shape)
 		default:
 			panic(fmt.Sprintf("unsupported shape type: %d", shape.ShapeType()))
 		}
-		for _, customDrawFunc := range o.options.CustomDrawFunc {
-			customDrawFunc(&o, imd)
-		}
+	}
+	for _, customDrawFunc := range o.options.CustomDrawFunc {
+		customDrawFunc(&o, imd)
 	}
 }
 
 func (o Object) drawCircle(imd *imdraw.IMDraw, shape *chipmunk.Shape) {
-	lower := shape.BB.Lower
-	upper := shape.BB.Upper
-	radius := shape.GetAsCircle().Radius
-
-	position := pixel.V(
-		float64(lower.X+upper.X)/2,
-		float64(lower.Y+upper.Y)/2,
-	)
+	center, radius := worldCircle(shape)
 
-	imd.Color = o.options.Color
-	imd.Push(position)
+	imd.Color = o.shapeColor(shape)
+	imd.Push(toPixel(center))
 	imd.Circle(float64(radius), o.options.Thickness)
 }
 
-func (o Object) drawBox(imd *imdraw.IMDraw, shape *chipmunk.Shape) {
-	lower := shape.BB.Lower
-	upper := shape.BB.Upper
-	angle := float64(shape.Body.Angle())
+func (o Object) drawPolygon(imd *imdraw.IMDraw, shape *chipmunk.Shape) {
+	imd.Color = o.shapeColor(shape)
+	for _, v := range worldVertices(shape) {
+		imd.Push(toPixel(v))
+	}
+	imd.Polygon(o.options.Thickness)
+}
+
+func (o Object) drawSegment(imd *imdraw.IMDraw, shape *chipmunk.Shape) {
+	a, b, radius := worldSegment(shape)
 
-	box := shape.GetAsBox()
-	width := float64(box.Width)
-	height := float64(box.Height)
+	endShape := imd.EndShape
+	imd.Color = o.shapeColor(shape)
+	imd.EndShape = imdraw.RoundEndShape
+	imd.Push(toPixel(a), toPixel(b))
+	imd.Line(math.Max(2*float64(radius), 1))
+	imd.EndShape = endShape
+}
 
-	for angle < 0 {
-		angle += 2 * math.Pi
-	}
-	for angle > math.Pi {
-		angle -= math.Pi
-	}
-	l := width
-	if angle > math.Pi/2 {
-		angle -= math.Pi / 2
-		l = height
-	}
-	sin, cos := math.Sincos(angle)
+// sensorAlpha is the opacity of sensor shapes
+const sensorAlpha = 0.5
 
-	corners := []pixel.Vec{
-		pixel.V(float64(upper.X)-l*cos, float64(lower.Y)),
-		pixel.V(float64(upper.X), float64(lower.Y)+l*sin),
-		pixel.V(float64(lower.X)+l*cos, float64(upper.Y)),
-		pixel.V(float64(lower.X), float64(upper.Y)-l*sin),
-	}
+// sensorDash is the length of the dashes in the outline of a sensor shape
+const sensorDash = 6.0
 
-	const debug = false
-	if debug {
-		imd.Color = colornames.White
-		imd.Push(pixel.V(float64(lower.X), float64(lower.Y)))
-		imd.Push(pixel.V(float64(upper.X), float64(upper.Y)))
-		imd.Rectangle(0)
+// drawSensor draws a sensor shape as a translucent, dashed outline
+func (o Object) drawSensor(imd *imdraw.IMDraw, shape *chipmunk.Shape) {
+	imd.Color = pixel.ToRGBA(o.shapeColor(shape)).Scaled(sensorAlpha)
+	thickness := max(o.options.Thickness, 1)
+
+	switch shape.ShapeType() {
+	case chipmunk.ShapeType_Circle:
+		center, radius := worldCircle(shape)
+		// one dash per sensorDash pixels of circumference, with gaps of the same length
+		step := sensorDash / float64(radius)
+		for angle := 0.0; angle < 2*math.Pi; angle += 2 * step {
+			imd.Push(toPixel(center))
+			imd.CircleArc(float64(radius), angle, math.Min(angle+step, 2*math.Pi), thickness)
+		}
+	case chipmunk.ShapeType_Box, chipmunk.ShapeType_Polygon:
+		vertices := worldVertices(shape)
+		for i, v := range vertices {
+			drawDashedLine(imd, toPixel(v), toPixel(vertices[(i+1)%len(vertices)]), sensorDash, thickness)
+		}
+	case chipmunk.ShapeType_Segment:
+		a, b, radius := worldSegment(shape)
+		drawDashedLine(imd, toPixel(a), toPixel(b), sensorDash, math.Max(2*float64(radius), thickness))
+	default:
+		panic(fmt.Sprintf("unsupported shape type: %d", shape.ShapeType()))
 	}
+}
 
-	imd.Color = o.options.Color
-	imd.Push(corners...)
-	imd.Polygon(o.options.Thickness)
+// shapeColor returns the color of a shape: shapes of a compound Object may override the Object's color
+func (o Object) shapeColor(shape *chipmunk.Shape) color.Color {
+	if spec, ok := shape.UserData.(ShapeSpec); ok && spec.Color != nil {
+		return spec.Color
+	}
+	return o.options.Color
 }
@@ -0,0 +1,103 @@
+package pixelmunk
+
+import (
+	"github.com/gopxl/pixel/v2/ext/imdraw"
+	"github.com/vova616/chipmunk"
+	"github.com/vova616/chipmunk/vect"
+)
+
+// PinJoint keeps the anchor points of two bodies at a fixed distance, as if they were connected by a rigid rod
+type PinJoint struct {
+	baseConstraint
+	pin *pinConstraint
+}
+
+var _ Drawable = &PinJoint{}
+
+// NewPinJoint creates a PinJoint between two Objects. The anchors are relative to their Object. The distance between
+// the anchors at the time the PinJoint is created, is kept constant.
+func NewPinJoint(a, b *Object, anchorA, anchorB vect.Vect, options DrawableOptions) *PinJoint {
+	pin := newPinConstraint(a.GetBody(), b.GetBody(), anchorA, anchorB)
+	return &PinJoint{
+		baseConstraint: baseConstraint{constraint: pin, options: options},
+		pin:            pin,
+	}
+}
+
+// Distance returns the distance that the PinJoint keeps between the two anchors
+func (j PinJoint) Distance() vect.Float {
+	return j.pin.dist
+}
+
+// Draw draws the PinJoint on the provided imdraw.IMDraw as a rod between the two anchors
+func (j PinJoint) Draw(imd *imdraw.IMDraw) {
+	j.drawPosed(imd, currentState)
+}
+
+// drawPosed draws the PinJoint with its bodies at the position and angle returned by pose
+func (j PinJoint) drawPosed(imd *imdraw.IMDraw, pose poseFunc) {
+	if j.options.JointOptions.Draw {
+		bodyA, bodyB := j.bodies()
+		poseA, poseB := pose(bodyA), pose(bodyB)
+		anchorA, anchorB := toPixel(poseA.toWorld(j.pin.anchorA)), toPixel(poseB.toWorld(j.pin.anchorB))
+
+		imd.Color = j.options.Color
+		drawLine(imd, anchorA, anchorB, j.options.Thickness)
+		drawAnchor(imd, anchorA)
+		drawAnchor(imd, anchorB)
+	}
+}
+
+type pinConstraint struct {
+	chipmunk.BasicConstraint
+	anchorA, anchorB vect.Vect
+	dist             vect.Float
+
+	r1, r2, n    vect.Vect
+	nMass, bias  vect.Float
+	jnAcc, jnMax vect.Float
+}
+
+func newPinConstraint(a, b *chipmunk.Body, anchorA, anchorB vect.Vect) *pinConstraint {
+	return &pinConstraint{
+		BasicConstraint: chipmunk.NewConstraint(a, b),
+		anchorA:         anchorA,
+		anchorB:         anchorB,
+		dist:            vect.Dist(toWorld(a, anchorA), toWorld(b, anchorB)),
+	}
+}
+
+func (c *pinConstraint) PreStep(dt vect.Float) {
+	a, b := c.BodyA, c.BodyB
+
+	c.r1 = rotate(a, c.anchorA)
+	c.r2 = rotate(b, c.anchorB)
+
+	delta := vect.Sub(vect.Add(b.Position(), c.r2), vect.Add(a.Position(), c.r1))
+	dist := vect.Length(delta)
+	c.n = normalize(delta, dist)
+
+	c.nMass = 1 / kScalar(a, b, c.r1, c.r2, c.n)
+	c.bias = clampFloat(-biasCoef(c.ErrorBias, dt)*(dist-c.dist)/dt, -c.MaxBias, c.MaxBias)
+	c.jnMax = c.MaxForce * dt
+}
+
+func (c *pinConstraint) ApplyCachedImpulse(dtCoef vect.Float) {
+	applyImpulses(c.BodyA, c.BodyB, c.r1, c.r2, vect.Mult(c.n, c.jnAcc*dtCoef))
+}
+
+func (c *pinConstraint) ApplyImpulse() {
+	a, b := c.BodyA, c.BodyB
+
+	vrn := normalRelativeVelocity(a, b, c.r1, c.r2, c.n)
+	jn := (c.bias - vrn) * c.nMass
+	jnOld := c.jnAcc
+	c.jnAcc = clampFloat(jnOld+jn, -c.jnMax, c.jnMax)
+	jn = c.jnAcc - jnOld
+
+	applyImpulses(a, b, c.r1, c.r2, vect.Mult(c.n, jn))
+}
+
+func (c *pinConstraint) Impulse() vect.Float {
+	return absFloat(c.jnAcc)
+}
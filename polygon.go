@@ -0,0 +1,68 @@
+package pixelmunk
+
+import (
+	"fmt"
+	"github.com/vova616/chipmunk"
+	"github.com/vova616/chipmunk/vect"
+)
+
+// NewPolygon creates a new Object for a convex polygon. The vertices may be specified in either winding order.
+// It returns an error if there are fewer than 3 vertices, or if the polygon is concave or has no area.
+func NewPolygon(options DrawableOptions) (*Object, error) {
+	if err := validatePolygon(options.BodyOptions.PolygonOptions.Vertices); err != nil {
+		return nil, err
+	}
+	options.BodyOptions.Type = chipmunk.ShapeType_Polygon
+	shape := chipmunk.NewPolygon(
+		clockwise(options.BodyOptions.PolygonOptions.Vertices),
+		vect.Vector_Zero,
+	)
+
+	return NewObjectWithShape(shape, options), nil
+}
+
+// validatePolygon returns an error if chipmunk can't build a polygon from the vertices
+func validatePolygon(vertices []vect.Vect) error {
+	if len(vertices) < 3 {
+		return fmt.Errorf("polygon: need at least 3 vertices, got %d", len(vertices))
+	}
+	if signedArea(vertices) == 0 {
+		return fmt.Errorf("polygon: vertices have no area")
+	}
+	if !clockwise(vertices).ValidatePolygon() {
+		return fmt.Errorf("polygon: vertices are not convex")
+	}
+	return nil
+}
+
+// clockwise returns the vertices in clockwise order, as required by chipmunk
+func clockwise(vertices []vect.Vect) chipmunk.Vertices {
+	if signedArea(vertices) <= 0 {
+		return vertices
+	}
+
+	reversed := make(chipmunk.Vertices, len(vertices))
+	for i, v := range vertices {
+		reversed[len(vertices)-1-i] = v
+	}
+	return reversed
+}
+
+// signedArea returns twice the signed area of the polygon: positive if the vertices are in counter-clockwise order
+func signedArea(vertices []vect.Vect) vect.Float {
+	var a vect.Float
+	for i := range vertices {
+		a += vect.Cross(vertices[i], vertices[(i+1)%len(vertices)])
+	}
+	return a
+}
+
+// centroid returns the centre of area of the polygon
+func centroid(vertices []vect.Vect) vect.Vect {
+	var sum vect.Vect
+	for i := range vertices {
+		v1, v2 := vertices[i], vertices[(i+1)%len(vertices)]
+		sum = vect.Add(sum, vect.Mult(vect.Add(v1, v2), vect.Cross(v1, v2)))
+	}
+	return vect.Mult(sum, 1/(3*signedArea(vertices)))
+}
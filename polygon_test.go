@@ -0,0 +1,47 @@
+package pixelmunk
+
+import (
+	"github.com/vova616/chipmunk/vect"
+	"testing"
+)
+
+func TestNewPolygon(t *testing.T) {
+	tests := []struct {
+		name     string
+		vertices []vect.Vect
+		wantErr  bool
+	}{
+		{name: "clockwise triangle", vertices: []vect.Vect{{X: 0, Y: 0}, {X: 0, Y: 10}, {X: 10, Y: 0}}},
+		{name: "counter-clockwise triangle", vertices: []vect.Vect{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 0, Y: 10}}},
+		{name: "square", vertices: []vect.Vect{{X: -5, Y: -5}, {X: 5, Y: -5}, {X: 5, Y: 5}, {X: -5, Y: 5}}},
+		{name: "no vertices", wantErr: true},
+		{name: "two vertices", vertices: []vect.Vect{{X: 0, Y: 0}, {X: 10, Y: 0}}, wantErr: true},
+		{name: "collinear", vertices: []vect.Vect{{X: 0, Y: 0}, {X: 5, Y: 0}, {X: 10, Y: 0}}, wantErr: true},
+		{name: "single point", vertices: []vect.Vect{{X: 1, Y: 1}, {X: 1, Y: 1}, {X: 1, Y: 1}}, wantErr: true},
+		{
+			name:     "concave",
+			vertices: []vect.Vect{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}, {X: 5, Y: 2}, {X: 0, Y: 10}},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			object, err := NewPolygon(DrawableOptions{BodyOptions: BodyOptions{
+				Mass:           1,
+				PolygonOptions: PolygonOptions{Vertices: tt.vertices},
+			}})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewPolygon: got error %v, want error: %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if object != nil {
+					t.Error("NewPolygon returned an Object with an error")
+				}
+				return
+			}
+			if i := object.GetBody().Moment(); i <= 0 {
+				t.Errorf("moment: got %v, want a positive moment", i)
+			}
+		})
+	}
+}
@@ -0,0 +1,379 @@
+package pixelmunk
+
+import (
+	"github.com/gopxl/pixel/v2"
+	"github.com/vova616/chipmunk"
+	"github.com/vova616/chipmunk/vect"
+	"math"
+	"slices"
+)
+
+// The queries below take world coordinates. They look up the shapes near the queried area in chipmunk's index, by
+// their bounding boxes, and then test the current position of each shape found (see geometry.go). The shapes of the
+// World's Objects are updated first, so bodies that were moved with SetPosition or SetAngle since the last step are
+// found where they are now. Sensor shapes are ignored.
+
+// RayHit describes where a ray hits an Object
+type RayHit struct {
+	Object *Object
+	// Point where the ray hits the Object, in world coordinates
+	Point vect.Vect
+	// Normal of the Object's surface at Point
+	Normal vect.Vect
+	// Fraction of the ray's length at which it hits the Object: 0 at the start of the ray, 1 at the end
+	Fraction vect.Float
+}
+
+// QueryPoint returns the Objects that contain the point
+func (w *World) QueryPoint(point vect.Vect) []*Object {
+	target := hull{vertices: []vect.Vect{point}}
+	return w.query(target.bb(), func(h hull) bool {
+		return h.distance(target) <= 0
+	})
+}
+
+// QueryRect returns the Objects that overlap the rectangle
+func (w *World) QueryRect(rect pixel.Rect) []*Object {
+	rect = rect.Norm()
+	target := hull{vertices: []vect.Vect{
+		toVect(rect.Min),
+		toVect(pixel.V(rect.Max.X, rect.Min.Y)),
+		toVect(rect.Max),
+		toVect(pixel.V(rect.Min.X, rect.Max.Y)),
+	}}
+	return w.query(target.bb(), func(h hull) bool {
+		return h.distance(target) <= 0
+	})
+}
+
+// QueryShape returns the Objects that overlap or touch the shapes of the provided Object. The Object doesn't need
+// to be part of the World, so QueryShape can be used to check if there is room for a new Object.
+func (w *World) QueryShape(object *Object) []*Object {
+	var targets []hull
+	var bb chipmunk.AABB
+	for i, shape := range object.GetBody().Shapes {
+		target := shapeHull(shape)
+		targets = append(targets, target)
+		if i == 0 {
+			bb = target.bb()
+		} else {
+			bb = chipmunk.Combine(bb, target.bb())
+		}
+	}
+	return w.query(bb, func(h hull) bool {
+		for _, target := range targets {
+			if h.distance(target) <= 0 {
+				return true
+			}
+		}
+		return false
+	}, object)
+}
+
+// RayCast returns the Objects hit by the ray from one point to another, ordered by distance from the start of
+// the ray. If the ray hits an Object more than once, only the first hit is returned. Objects that contain
+// the start of the ray are ignored.
+func (w *World) RayCast(from, to vect.Vect) []RayHit {
+	var hits []RayHit
+	w.forEachShape(hull{vertices: []vect.Vect{from, to}}.bb(), func(object *Object, shape *chipmunk.Shape) {
+		hit, ok := shapeHull(shape).rayCast(from, to)
+		if !ok {
+			return
+		}
+		hit.Object = object
+		for i := range hits {
+			if hits[i].Object == object {
+				if hit.Fraction < hits[i].Fraction {
+					hits[i] = hit
+				}
+				return
+			}
+		}
+		hits = append(hits, hit)
+	})
+	slices.SortStableFunc(hits, func(a, b RayHit) int {
+		switch {
+		case a.Fraction < b.Fraction:
+			return -1
+		case a.Fraction > b.Fraction:
+			return 1
+		default:
+			return 0
+		}
+	})
+	return hits
+}
+
+// query returns the Objects with a shape in the bounding box that matches, in the order in which they were added to
+// the World
+func (w *World) query(bb chipmunk.AABB, match func(hull) bool, exclude ...*Object) []*Object {
+	var objects []*Object
+	w.forEachShape(bb, func(object *Object, shape *chipmunk.Shape) {
+		if slices.Contains(exclude, object) || (len(objects) > 0 && objects[len(objects)-1] == object) {
+			return
+		}
+		if match(shapeHull(shape)) {
+			objects = append(objects, object)
+		}
+	})
+	return objects
+}
+
+// forEachShape calls f for every shape of the World's Objects, except for sensors, that chipmunk's index finds in
+// the bounding box. The shapes are passed in the order of their Objects in the World.
+func (w *World) forEachShape(bb chipmunk.AABB, f func(*Object, *chipmunk.Shape)) {
+	w.useSweepIndex()
+	for _, drawable := range w.Objects {
+		if drawable.GetType() == DrawableBody {
+			drawable.GetBody().UpdateShapes()
+		}
+	}
+	found := make(map[*chipmunk.Shape]bool)
+	collect := func(_, shape chipmunk.Indexable) {
+		found[shape.Shape()] = true
+	}
+	w.Space.Query(nil, bb, collect)
+	w.Space.QueryStatic(nil, bb, collect)
+	if len(found) == 0 {
+		return
+	}
+	for _, drawable := range w.Objects {
+		if drawable.GetType() != DrawableBody {
+			continue
+		}
+		object := w.bodyObjects[drawable.GetBody()]
+		if object == nil {
+			continue
+		}
+		for _, shape := range object.GetBody().Shapes {
+			if found[shape] && !shape.IsSensor {
+				f(object, shape)
+			}
+		}
+	}
+}
+
+// hull is a convex polygon, in world coordinates, grown by a radius. This covers all chipmunk shapes: a circle is
+// a single vertex with a radius, a segment two vertices with a radius and a box or polygon has no radius.
+type hull struct {
+	vertices []vect.Vect
+	radius   vect.Float
+}
+
+func shapeHull(shape *chipmunk.Shape) hull {
+	switch shape.ShapeType() {
+	case chipmunk.ShapeType_Circle:
+		center, radius := worldCircle(shape)
+		return hull{vertices: []vect.Vect{center}, radius: radius}
+	case chipmunk.ShapeType_Segment:
+		a, b, radius := worldSegment(shape)
+		return hull{vertices: []vect.Vect{a, b}, radius: radius}
+	default:
+		return hull{vertices: worldVertices(shape)}
+	}
+}
+
+// bb returns the bounding box of the hull
+func (h hull) bb() chipmunk.AABB {
+	low, high := h.vertices[0], h.vertices[0]
+	for _, v := range h.vertices[1:] {
+		low = vect.Vect{X: min(low.X, v.X), Y: min(low.Y, v.Y)}
+		high = vect.Vect{X: max(high.X, v.X), Y: max(high.Y, v.Y)}
+	}
+	return chipmunk.NewAABB(low.X-h.radius, low.Y-h.radius, high.X+h.radius, high.Y+h.radius)
+}
+
+// edges returns the edges of the hull. A single vertex is a zero-length edge.
+func (h hull) edges() [][2]vect.Vect {
+	switch len(h.vertices) {
+	case 0:
+		return nil
+	case 1:
+		return [][2]vect.Vect{{h.vertices[0], h.vertices[0]}}
+	case 2:
+		return [][2]vect.Vect{{h.vertices[0], h.vertices[1]}}
+	}
+	edges := make([][2]vect.Vect, len(h.vertices))
+	for i, v := range h.vertices {
+		edges[i] = [2]vect.Vect{v, h.vertices[(i+1)%len(h.vertices)]}
+	}
+	return edges
+}
+
+// distance returns the distance between the two hulls, or zero if they overlap
+func (h hull) distance(other hull) vect.Float {
+	if h.overlaps(other) {
+		return 0
+	}
+	d := vect.Float(math.Inf(1))
+	for _, edge := range other.edges() {
+		for _, v := range h.vertices {
+			d = min(d, segmentDistance(v, edge[0], edge[1]))
+		}
+	}
+	for _, edge := range h.edges() {
+		for _, v := range other.vertices {
+			d = min(d, segmentDistance(v, edge[0], edge[1]))
+		}
+	}
+	return max(d-h.radius-other.radius, 0)
+}
+
+// overlaps reports whether the polygons of the two hulls (ignoring their radius) overlap, using the separating
+// axis theorem
+func (h hull) overlaps(other hull) bool {
+	axes := append(h.axes(), other.axes()...)
+	if len(axes) == 0 {
+		return false
+	}
+	for _, axis := range axes {
+		minA, maxA := project(h.vertices, axis)
+		minB, maxB := project(other.vertices, axis)
+		if maxA < minB || maxB < minA {
+			return false
+		}
+	}
+	return true
+}
+
+// axes returns the axes to test for separation: the normals of the hull's edges and, for a segment, its direction
+func (h hull) axes() []vect.Vect {
+	var axes []vect.Vect
+	for _, edge := range h.edges() {
+		if d := vect.Sub(edge[1], edge[0]); d != (vect.Vect{}) {
+			axes = append(axes, vect.Perp(d))
+			if len(h.vertices) == 2 {
+				axes = append(axes, d)
+			}
+		}
+	}
+	return axes
+}
+
+func project(vertices []vect.Vect, axis vect.Vect) (vect.Float, vect.Float) {
+	low, high := vect.Float(math.Inf(1)), vect.Float(math.Inf(-1))
+	for _, v := range vertices {
+		p := vect.Dot(v, axis)
+		low, high = min(low, p), max(high, p)
+	}
+	return low, high
+}
+
+// segmentDistance returns the distance between point p and the segment from a to b
+func segmentDistance(p, a, b vect.Vect) vect.Float {
+	return vect.Dist(p, closestPoint(p, a, b))
+}
+
+// closestPoint returns the point on the segment from a to b that is closest to p
+func closestPoint(p, a, b vect.Vect) vect.Vect {
+	ab := vect.Sub(b, a)
+	lengthSqr := vect.LengthSqr(ab)
+	if lengthSqr == 0 {
+		return a
+	}
+	t := clampFloat(vect.Dot(vect.Sub(p, a), ab)/lengthSqr, 0, 1)
+	return vect.Add(a, vect.Mult(ab, t))
+}
+
+// rayCast returns where the ray from one point to another first enters the hull
+func (h hull) rayCast(from, to vect.Vect) (RayHit, bool) {
+	switch len(h.vertices) {
+	case 1:
+		return rayCastCircle(from, to, h.vertices[0], h.radius)
+	case 2:
+		return rayCastCapsule(from, to, h.vertices[0], h.vertices[1], h.radius)
+	default:
+		return rayCastPolygon(from, to, h.vertices)
+	}
+}
+
+func rayCastCircle(from, to, center vect.Vect, radius vect.Float) (RayHit, bool) {
+	d := vect.Sub(to, from)
+	f := vect.Sub(from, center)
+	a := vect.Dot(d, d)
+	b := 2 * vect.Dot(f, d)
+	c := vect.Dot(f, f) - radius*radius
+	discriminant := b*b - 4*a*c
+	if c < 0 || a == 0 || discriminant < 0 {
+		return RayHit{}, false
+	}
+	t := (-b - vect.Float(math.Sqrt(float64(discriminant)))) / (2 * a)
+	if t < 0 || t > 1 {
+		return RayHit{}, false
+	}
+	point := vect.Add(from, vect.Mult(d, t))
+	return RayHit{Point: point, Normal: normalize(vect.Sub(point, center), radius), Fraction: t}, true
+}
+
+func rayCastCapsule(from, to, a, b vect.Vect, radius vect.Float) (RayHit, bool) {
+	if segmentDistance(from, a, b) < radius {
+		return RayHit{}, false
+	}
+
+	var best RayHit
+	found := false
+	try := func(hit RayHit, ok bool) {
+		if ok && (!found || hit.Fraction < best.Fraction) {
+			best, found = hit, true
+		}
+	}
+
+	try(rayCastCircle(from, to, a, radius))
+	try(rayCastCircle(from, to, b, radius))
+	if ab := vect.Sub(b, a); ab != (vect.Vect{}) {
+		n := vect.Mult(normalize(vect.Perp(ab), vect.Length(ab)), radius)
+		// the two sides of the capsule, as thin polygons
+		try(rayCastPolygon(from, to, []vect.Vect{vect.Add(a, n), vect.Add(b, n), b, a}))
+		try(rayCastPolygon(from, to, []vect.Vect{vect.Sub(a, n), vect.Sub(b, n), b, a}))
+	}
+	return best, found
+}
+
+// rayCastPolygon clips the ray against each edge of the convex polygon (Cyrus-Beck)
+func rayCastPolygon(from, to vect.Vect, vertices []vect.Vect) (RayHit, bool) {
+	// outward normals depend on the winding of the polygon
+	var area vect.Float
+	for i, v := range vertices {
+		area += vect.Cross(v, vertices[(i+1)%len(vertices)])
+	}
+	sign := vect.Float(1)
+	if area > 0 {
+		sign = -1
+	}
+
+	d := vect.Sub(to, from)
+	enter, exit := vect.Float(0), vect.Float(1)
+	var normal vect.Vect
+	// a ray that starts inside the polygon doesn't enter it through any edge. A ray that starts on an edge and
+	// points inwards enters it at fraction 0.
+	entered := false
+	for i, v := range vertices {
+		edge := vect.Sub(vertices[(i+1)%len(vertices)], v)
+		n := vect.Mult(vect.Perp(edge), sign)
+		num := vect.Dot(n, vect.Sub(v, from))
+		den := vect.Dot(n, d)
+		switch {
+		case den == 0:
+			if num < 0 {
+				return RayHit{}, false
+			}
+		case den < 0:
+			if t := num / den; t >= enter {
+				enter, normal, entered = t, n, true
+			}
+		default:
+			exit = min(exit, num/den)
+		}
+		if enter > exit {
+			return RayHit{}, false
+		}
+	}
+	if !entered {
+		return RayHit{}, false
+	}
+	return RayHit{
+		Point:    vect.Add(from, vect.Mult(d, enter)),
+		Normal:   normalize(normal, vect.Length(normal)),
+		Fraction: enter,
+	}, true
+}
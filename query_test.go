@@ -0,0 +1,195 @@
+package pixelmunk
+
+import (
+	"github.com/gopxl/pixel/v2"
+	"github.com/vova616/chipmunk/vect"
+	"slices"
+	"testing"
+)
+
+// queryWorld creates a World with a circle, a rotated box, a floor and a sensor, without gravity
+func queryWorld() (w *World, circle, box, floor, sensor *Object) {
+	w = NewWorld("query", 0, 0, 100, 100)
+	circle = NewCircle(DrawableOptions{BodyOptions: BodyOptions{
+		Position:      vect.Vect{X: 20, Y: 50},
+		Mass:          1,
+		CircleOptions: CircleOptions{Radius: 10},
+	}})
+	box = NewBox(DrawableOptions{BodyOptions: BodyOptions{
+		Position:   vect.Vect{X: 60, Y: 50},
+		Angle:      0.5,
+		Mass:       1,
+		BoxOptions: BoxOptions{Width: 20, Height: 20},
+	}})
+	floor = NewSegment(DrawableOptions{BodyOptions: BodyOptions{
+		StaticBody:     true,
+		SegmentOptions: SegmentOptions{A: vect.Vect{X: 0, Y: 10}, B: vect.Vect{X: 100, Y: 10}, Radius: 2},
+	}})
+	sensor = NewCircle(DrawableOptions{BodyOptions: BodyOptions{
+		StaticBody:    true,
+		Position:      vect.Vect{X: 60, Y: 50},
+		Sensor:        true,
+		CircleOptions: CircleOptions{Radius: 30},
+	}})
+	w.Add(circle, box, floor, sensor)
+	return w, circle, box, floor, sensor
+}
+
+func TestWorld_QueryPoint(t *testing.T) {
+	w, circle, box, floor, _ := queryWorld()
+	tests := []struct {
+		name  string
+		point vect.Vect
+		want  []*Object
+	}{
+		{name: "circle", point: vect.Vect{X: 25, Y: 55}, want: []*Object{circle}},
+		{name: "box", point: vect.Vect{X: 60, Y: 50}, want: []*Object{box}},
+		// the corner of the box without its rotation
+		{name: "outside the rotated box", point: vect.Vect{X: 69, Y: 59}},
+		{name: "radius of the floor", point: vect.Vect{X: 50, Y: 11.5}, want: []*Object{floor}},
+		{name: "nothing, except the sensor", point: vect.Vect{X: 80, Y: 60}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := w.QueryPoint(tt.point); !slices.Equal(got, tt.want) {
+				t.Errorf("QueryPoint: got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWorld_QueryRect(t *testing.T) {
+	w, circle, box, floor, _ := queryWorld()
+	tests := []struct {
+		name string
+		rect pixel.Rect
+		want []*Object
+	}{
+		{name: "everything", rect: pixel.R(0, 0, 100, 100), want: []*Object{circle, box, floor}},
+		{name: "not normalized", rect: pixel.R(100, 100, 0, 0), want: []*Object{circle, box, floor}},
+		{name: "circle and box", rect: pixel.R(25, 45, 55, 55), want: []*Object{circle, box}},
+		{name: "between the circle and the floor", rect: pixel.R(10, 15, 30, 35)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := w.QueryRect(tt.rect); !slices.Equal(got, tt.want) {
+				t.Errorf("QueryRect: got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWorld_QueryShape(t *testing.T) {
+	w, circle, box, floor, _ := queryWorld()
+	tests := []struct {
+		name   string
+		object *Object
+		want   []*Object
+	}{
+		{
+			name: "wide box",
+			object: NewBox(DrawableOptions{BodyOptions: BodyOptions{
+				Position:   vect.Vect{X: 40, Y: 50},
+				Mass:       1,
+				BoxOptions: BoxOptions{Width: 30, Height: 4},
+			}}),
+			want: []*Object{circle, box},
+		},
+		{
+			name: "touching the floor",
+			object: NewCircle(DrawableOptions{BodyOptions: BodyOptions{
+				Position:      vect.Vect{X: 90, Y: 17},
+				Mass:          1,
+				CircleOptions: CircleOptions{Radius: 5},
+			}}),
+			want: []*Object{floor},
+		},
+		{
+			name: "room",
+			object: NewCircle(DrawableOptions{BodyOptions: BodyOptions{
+				Position:      vect.Vect{X: 20, Y: 25},
+				Mass:          1,
+				CircleOptions: CircleOptions{Radius: 5},
+			}}),
+		},
+		{name: "an Object doesn't overlap itself", object: circle},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := w.QueryShape(tt.object); !slices.Equal(got, tt.want) {
+				t.Errorf("QueryShape: got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWorld_RayCast(t *testing.T) {
+	w, circle, box, floor, _ := queryWorld()
+	// the middle of the left edge of the box, which is rotated by 0.5 radians
+	left := toWorld(box.GetBody(), vect.Vect{X: -10})
+	inwards := toWorld(box.GetBody(), vect.Vect{X: 10})
+	outwards := toWorld(box.GetBody(), vect.Vect{X: -30})
+	tests := []struct {
+		name     string
+		from, to vect.Vect
+		want     []*Object
+		// fraction of the first hit
+		fraction vect.Float
+	}{
+		{
+			name: "through the circle and the box",
+			from: vect.Vect{X: 0, Y: 50}, to: vect.Vect{X: 100, Y: 50},
+			want:     []*Object{circle, box},
+			fraction: 0.1,
+		},
+		{
+			name: "down to the floor",
+			from: vect.Vect{X: 20, Y: 50}, to: vect.Vect{X: 20, Y: 0},
+			// the ray starts inside the circle
+			want:     []*Object{floor},
+			fraction: 0.76,
+		},
+		{name: "starts on the edge of the box, inwards", from: left, to: inwards, want: []*Object{box}, fraction: 0},
+		{name: "starts on the edge of the box, outwards", from: left, to: outwards},
+		{name: "misses", from: vect.Vect{X: 0, Y: 90}, to: vect.Vect{X: 100, Y: 90}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hits := w.RayCast(tt.from, tt.to)
+			var got []*Object
+			for _, hit := range hits {
+				got = append(got, hit.Object)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Fatalf("RayCast: got %v, want %v", got, tt.want)
+			}
+			if len(hits) > 0 && !approx(hits[0].Fraction, tt.fraction) {
+				t.Errorf("fraction: got %v, want %v", hits[0].Fraction, tt.fraction)
+			}
+		})
+	}
+}
+
+func TestWorld_QueryPoint_moved(t *testing.T) {
+	w, circle, _, _, _ := queryWorld()
+	at := vect.Vect{X: 80, Y: 80}
+	// moved after the last step: chipmunk's bounding box is still at the old position
+	circle.GetBody().SetPosition(at)
+	if got := w.QueryPoint(at); !slices.Equal(got, []*Object{circle}) {
+		t.Errorf("after SetPosition: got %v, want the circle", got)
+	}
+	if got := w.QueryRect(pixel.R(10, 40, 30, 60)); len(got) != 0 {
+		t.Errorf("old position: got %v, want nothing", got)
+	}
+
+	// Step updates the shapes of all dynamic bodies
+	circle.GetBody().SetPosition(vect.Vect{X: 20, Y: 50})
+	w.Step(w.physicsDt())
+	if got := w.QueryPoint(at); len(got) != 0 {
+		t.Errorf("after Step: got %v, want nothing", got)
+	}
+}
+
+func approx(a, b vect.Float) bool {
+	return vect.FAbs(a-b) < 1e-4
+}
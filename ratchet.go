@@ -0,0 +1,117 @@
+package pixelmunk
+
+import (
+	"github.com/gopxl/pixel/v2"
+	"github.com/gopxl/pixel/v2/ext/imdraw"
+	"github.com/vova616/chipmunk"
+	"github.com/vova616/chipmunk/vect"
+	"math"
+)
+
+// Ratchet lets two bodies turn relative to each other in one direction only, clicking into place every ratchet radians
+type Ratchet struct {
+	baseConstraint
+	ratchet *ratchetConstraint
+}
+
+var _ Drawable = &Ratchet{}
+
+// NewRatchet creates a Ratchet between two Objects. phase is the initial offset of the teeth and ratchet is the
+// distance between them, in radians. The sign of ratchet sets the direction in which b may turn relative to a.
+func NewRatchet(a, b *Object, phase, ratchet vect.Float, options DrawableOptions) *Ratchet {
+	r := newRatchetConstraint(a.GetBody(), b.GetBody(), phase, ratchet)
+	return &Ratchet{
+		baseConstraint: baseConstraint{constraint: r, options: options},
+		ratchet:        r,
+	}
+}
+
+// maxRatchetTeeth limits the number of teeth drawn for ratchets with a very small step
+const maxRatchetTeeth = 32
+
+// Draw draws the Ratchet on the provided imdraw.IMDraw as a toothed wheel around the second body, with a spoke
+// for the current angle of the second body
+func (r Ratchet) Draw(imd *imdraw.IMDraw) {
+	r.drawPosed(imd, currentState)
+}
+
+// drawPosed draws the Ratchet with its bodies at the position and angle returned by pose
+func (r Ratchet) drawPosed(imd *imdraw.IMDraw, pose poseFunc) {
+	if r.options.JointOptions.Draw {
+		bodyA, bodyB := r.bodies()
+		poseA, poseB := pose(bodyA), pose(bodyB)
+		centre := toPixel(poseB.position)
+		stop := float64(poseA.angle + r.ratchet.angle)
+		step := math.Abs(float64(r.ratchet.ratchet))
+
+		imd.Color = r.options.Color
+		teeth := min(int(2*math.Pi/step), maxRatchetTeeth)
+		for i := 0; i < teeth; i++ {
+			direction := pixel.V(1, 0).Rotated(stop + float64(i)*step)
+			drawLine(imd, centre.Add(direction.Scaled(rotaryRadius*0.7)), centre.Add(direction.Scaled(rotaryRadius)), r.options.Thickness)
+		}
+		drawSpoke(imd, centre, rotaryRadius, float64(poseB.angle), r.options.Thickness)
+	}
+}
+
+type ratchetConstraint struct {
+	chipmunk.BasicConstraint
+	angle, phase, ratchet vect.Float
+
+	iSum, bias vect.Float
+	jAcc, jMax vect.Float
+}
+
+func newRatchetConstraint(a, b *chipmunk.Body, phase, ratchet vect.Float) *ratchetConstraint {
+	return &ratchetConstraint{
+		BasicConstraint: chipmunk.NewConstraint(a, b),
+		angle:           b.Angle() - a.Angle(),
+		phase:           phase,
+		ratchet:         ratchet,
+	}
+}
+
+func (c *ratchetConstraint) PreStep(dt vect.Float) {
+	a, b := c.BodyA, c.BodyB
+
+	delta := b.Angle() - a.Angle()
+	diff := c.angle - delta
+
+	var pdist vect.Float
+	if diff*c.ratchet > 0 {
+		pdist = diff
+	} else {
+		c.angle = vect.Float(math.Floor(float64((delta-c.phase)/c.ratchet)))*c.ratchet + c.phase
+	}
+
+	c.iSum = 1 / (invMoment(a) + invMoment(b))
+	c.bias = clampFloat(-biasCoef(c.ErrorBias, dt)*pdist/dt, -c.MaxBias, c.MaxBias)
+	c.jMax = c.MaxForce * dt
+
+	// not at a tooth: nothing to carry over to the next step
+	if c.bias == 0 {
+		c.jAcc = 0
+	}
+}
+
+func (c *ratchetConstraint) ApplyCachedImpulse(dtCoef vect.Float) {
+	applyAngularImpulses(c.BodyA, c.BodyB, c.jAcc*dtCoef)
+}
+
+func (c *ratchetConstraint) ApplyImpulse() {
+	if c.bias == 0 {
+		return
+	}
+	a, b := c.BodyA, c.BodyB
+
+	wr := angularVelocity(b) - angularVelocity(a)
+	j := -(c.bias + wr) * c.iSum
+	jOld := c.jAcc
+	c.jAcc = clampFloat((jOld+j)*c.ratchet, 0, c.jMax*absFloat(c.ratchet)) / c.ratchet
+
+	applyAngularImpulses(a, b, c.jAcc-jOld)
+}
+
+func (c *ratchetConstraint) Impulse() vect.Float {
+	return absFloat(c.jAcc)
+}
@@ -0,0 +1,293 @@
+package pixelmunk
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/vova616/chipmunk/vect"
+	"hash/fnv"
+	"io"
+	"math"
+)
+
+// replayVersion is the version of the replay format written by SaveReplay
+const replayVersion = 1
+
+// checksumInterval is the number of steps between the checksums in a recording, so a replay that diverges is
+// detected close to where it happened
+const checksumInterval = 60
+
+// ErrReplayDiverged is returned by RunHeadless and ReplayErr if a replayed World doesn't follow its recording,
+// e.g. because the application spawned different Objects, or because its physics gave a different result
+var ErrReplayDiverged = errors.New("replay diverged")
+
+// replay is a recording of a simulation that is being made or played back
+type replay struct {
+	replayFile
+	playing   bool
+	nextInput int
+	nextSpawn int
+	err       error
+}
+
+// A replayFile is the recording of a simulation: the input that triggered key bindings, scroll bindings and mouse
+// drags, and the initial state of spawned bodies, each with the index of the step before which it happened. A
+// replayed World gets all its input from the recording, so it follows the same path as the recorded one.
+type replayFile struct {
+	Version int `json:"version"`
+	Steps   int `json:"steps"`
+	// Checksum of the state of all Objects after the last step
+	Checksum string `json:"checksum"`
+	// Checksums of the state of all Objects after every checksumInterval steps
+	Checksums []string      `json:"checksums,omitempty"`
+	Inputs    []replayInput `json:"inputs,omitempty"`
+	Spawns    []replaySpawn `json:"spawns,omitempty"`
+}
+
+type replayInput struct {
+	Step  int      `json:"step"`
+	Mouse sceneVec `json:"mouse"`
+	// Binding is the index of the triggered key binding
+	Binding *int        `json:"binding,omitempty"`
+	Scroll  *sceneVec   `json:"scroll,omitempty"`
+	Drag    *replayDrag `json:"drag,omitempty"`
+}
+
+type replayDrag struct {
+	Pressed     bool `json:"pressed,omitempty"`
+	JustPressed bool `json:"justPressed,omitempty"`
+}
+
+type replaySpawn struct {
+	Step   int          `json:"step"`
+	Bodies []replayBody `json:"bodies,omitempty"`
+}
+
+type replayBody struct {
+	Position        sceneVec   `json:"position"`
+	Angle           vect.Float `json:"angle,omitempty"`
+	Velocity        sceneVec   `json:"velocity"`
+	AngularVelocity vect.Float `json:"angularVelocity,omitempty"`
+}
+
+// StartRecording records all input handled by the default run loop and all Objects added with Spawn, so the
+// simulation can be replayed with LoadReplay. The recording starts from the state in which the application creates
+// the World, so StartRecording must be called before the World is stepped.
+//
+// Debug keys that change the pace of the simulation (pause, TimeScale) don't affect the recording. Rewinding does,
+// so Rewind is disabled while recording or replaying. Restoring a Snapshot makes the replay diverge.
+func (w *World) StartRecording() error {
+	if w.steps > 0 {
+		return fmt.Errorf("replay: world has already been stepped %d times", w.steps)
+	}
+	w.replay = &replay{replayFile: replayFile{Version: replayVersion}}
+	return nil
+}
+
+// SaveReplay writes the recording started by StartRecording, up to the current step, as JSON
+func (w *World) SaveReplay(out io.Writer) error {
+	if !w.recording() {
+		return errors.New("replay: not recording")
+	}
+	w.replay.Steps = w.steps
+	w.replay.Checksum = w.checksum()
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(w.replay.replayFile)
+}
+
+// LoadReplay replays a recording written by SaveReplay. The World must be set up by the same application code as
+// the recorded one and must not have been stepped yet. Until the end of the recording, the World ignores the
+// keyboard and mouse and Spawn gives spawned bodies their recorded state. The replay can be run in a window or
+// with RunHeadless.
+func (w *World) LoadReplay(in io.Reader) error {
+	if w.steps > 0 {
+		return fmt.Errorf("replay: world has already been stepped %d times", w.steps)
+	}
+	var f replayFile
+	if err := json.NewDecoder(in).Decode(&f); err != nil {
+		return fmt.Errorf("replay: %w", err)
+	}
+	if f.Version != replayVersion {
+		return fmt.Errorf("replay: unsupported version %d", f.Version)
+	}
+	w.replay = &replay{replayFile: f, playing: true}
+	return nil
+}
+
+// Replaying reports whether the World is replaying a recording that hasn't ended yet
+func (w *World) Replaying() bool {
+	return w.replay != nil && w.replay.playing && w.steps < w.replay.Steps
+}
+
+// ReplayErr returns ErrReplayDiverged, wrapped with the reason, if the replayed World didn't follow the recording
+func (w *World) ReplayErr() error {
+	if w.replay == nil {
+		return nil
+	}
+	return w.replay.err
+}
+
+// Spawn adds Objects to the World, like Add, for Objects that the application creates while the simulation runs,
+// e.g. at random positions. When recording, Spawn records the initial position, angle and velocities of their
+// bodies; when replaying, it gives them their recorded state instead. Anything else about spawned Objects (e.g.
+// their size) must not depend on randomness.
+func (w *World) Spawn(objects ...Drawable) {
+	if w.replay != nil {
+		spawn := replaySpawn{Step: w.steps}
+		for _, object := range objects {
+			if object.GetType() == DrawableBody {
+				body := object.GetBody()
+				spawn.Bodies = append(spawn.Bodies, replayBody{
+					Position:        toSceneVec(body.Position()),
+					Angle:           body.Angle(),
+					Velocity:        toSceneVec(body.Velocity()),
+					AngularVelocity: angularVelocity(body),
+				})
+			}
+		}
+		if w.recording() {
+			w.replay.Spawns = append(w.replay.Spawns, spawn)
+		} else {
+			w.replaySpawn(spawn, objects)
+		}
+	}
+	w.Add(objects...)
+}
+
+// replaySpawn gives the bodies of the spawned objects their recorded state
+func (w *World) replaySpawn(spawn replaySpawn, objects []Drawable) {
+	r := w.replay
+	if !w.Replaying() {
+		return
+	}
+	if r.nextSpawn >= len(r.Spawns) || r.Spawns[r.nextSpawn].Step != spawn.Step ||
+		len(r.Spawns[r.nextSpawn].Bodies) != len(spawn.Bodies) {
+		w.replayDiverged("unexpected spawn at step %d", spawn.Step)
+		return
+	}
+	recorded := r.Spawns[r.nextSpawn].Bodies
+	r.nextSpawn++
+	var i int
+	for _, object := range objects {
+		if object.GetType() != DrawableBody {
+			continue
+		}
+		body, state := object.GetBody(), recorded[i]
+		body.SetPosition(state.Position.vect())
+		body.SetAngle(state.Angle)
+		body.SetVelocity(float32(state.Velocity.X), float32(state.Velocity.Y))
+		body.SetAngularVelocity(float32(state.AngularVelocity))
+		i++
+	}
+}
+
+func (w *World) recording() bool {
+	return w.replay != nil && !w.replay.playing
+}
+
+// recordInput records the input of a key binding, scroll binding or mouse drag
+func (w *World) recordInput(input replayInput) {
+	if w.recording() {
+		input.Step = w.steps
+		input.Mouse = toSceneVec(w.mousePosition)
+		w.replay.Inputs = append(w.replay.Inputs, input)
+	}
+}
+
+// replayInputs handles the recorded input for the next step
+func (w *World) replayInputs() {
+	if !w.Replaying() {
+		return
+	}
+	r := w.replay
+	for ; r.nextInput < len(r.Inputs) && r.Inputs[r.nextInput].Step <= w.steps; r.nextInput++ {
+		input := r.Inputs[r.nextInput]
+		w.mousePosition = input.Mouse.vect()
+		switch {
+		case input.Binding != nil:
+			if *input.Binding < 0 || *input.Binding >= len(w.keyBindings) {
+				w.replayDiverged("unknown key binding %d at step %d", *input.Binding, input.Step)
+				continue
+			}
+			w.keyBindings[*input.Binding].action(w)
+		case input.Scroll != nil:
+			for _, action := range w.scrollBindings {
+				action(w, toPixel(input.Scroll.vect()))
+			}
+		case input.Drag != nil:
+			w.dragMouse(w.mousePosition, input.Drag.Pressed, input.Drag.JustPressed, 1/vect.Float(w.FrameRate))
+		}
+	}
+}
+
+// checkReplay records the checksum after every checksumInterval steps or, when replaying, checks that the replayed
+// World is in the recorded state
+func (w *World) checkReplay() {
+	r := w.replay
+	if r == nil || w.steps%checksumInterval != 0 {
+		return
+	}
+	i := w.steps/checksumInterval - 1
+	switch {
+	case w.recording():
+		r.Checksums = append(r.Checksums, w.checksum())
+	case i < len(r.Checksums) && w.steps <= r.Steps:
+		if checksum := w.checksum(); checksum != r.Checksums[i] {
+			w.replayDiverged("checksum %s at step %d, expected %s", checksum, w.steps, r.Checksums[i])
+		}
+	}
+}
+
+// endReplay checks that the replayed World ended up in the recorded state
+func (w *World) endReplay() {
+	r := w.replay
+	if r == nil || !r.playing || w.steps != r.Steps {
+		return
+	}
+	if r.nextSpawn < len(r.Spawns) {
+		w.replayDiverged("%d recorded spawns were not replayed", len(r.Spawns)-r.nextSpawn)
+	}
+	if checksum := w.checksum(); checksum != r.Checksum {
+		w.replayDiverged("checksum %s at step %d, expected %s", checksum, w.steps, r.Checksum)
+	}
+}
+
+func (w *World) replayDiverged(format string, args ...any) {
+	if w.replay.err == nil {
+		w.replay.err = fmt.Errorf("%w: "+format, append([]any{ErrReplayDiverged}, args...)...)
+	}
+}
+
+// checksum returns a hash of the position, angle and velocities of all bodies in the World
+func (w *World) checksum() string {
+	h := fnv.New64a()
+	for _, object := range w.Objects {
+		if object.GetType() != DrawableBody {
+			continue
+		}
+		body := object.GetBody()
+		position, velocity := body.Position(), body.Velocity()
+		for _, f := range []vect.Float{position.X, position.Y, body.Angle(), velocity.X, velocity.Y, angularVelocity(body)} {
+			_ = binary.Write(h, binary.LittleEndian, math.Float32bits(float32(f)))
+		}
+	}
+	return fmt.Sprintf("%016x", h.Sum64())
+}
+
+// replayStatus describes the state of a replay, for the window title
+func (w *World) replayStatus() string {
+	switch {
+	case w.replay == nil:
+		return ""
+	case w.replay.err != nil:
+		return " [replay diverged]"
+	case w.Replaying():
+		return " [replay]"
+	case w.recording():
+		return " [recording]"
+	default:
+		return ""
+	}
+}
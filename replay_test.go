@@ -0,0 +1,126 @@
+package pixelmunk
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/gopxl/pixel/v2"
+	"github.com/vova616/chipmunk/vect"
+	"math/rand"
+	"testing"
+)
+
+// fakeInput is an input with the buttons that are held down in the current frame
+type fakeInput struct {
+	pressed map[pixel.Button]bool
+}
+
+func (in fakeInput) Pressed(b pixel.Button) bool     { return in.pressed[b] }
+func (in fakeInput) JustPressed(b pixel.Button) bool { return in.pressed[b] }
+func (in fakeInput) JustReleased(pixel.Button) bool  { return false }
+func (in fakeInput) Repeated(pixel.Button) bool      { return false }
+func (in fakeInput) MousePosition() pixel.Vec        { return pixel.Vec{} }
+func (in fakeInput) MouseScroll() pixel.Vec          { return pixel.Vec{} }
+
+// catchWorld creates a World in which balls are spawned at random positions above a floor, to be caught by a cup
+// that is moved with the arrow keys
+func catchWorld(seed int64, spawnInterval int, friction vect.Float) *World {
+	random := rand.New(rand.NewSource(seed))
+	w := NewWorld("catch", 0, 0, 400, 300)
+	w.Space.Gravity = vect.Vect{Y: -900}
+	w.Add(
+		NewBox(DrawableOptions{BodyOptions: BodyOptions{
+			StaticBody: true,
+			Position:   vect.Vect{X: 200, Y: 5},
+			Friction:   friction,
+			BoxOptions: BoxOptions{Width: 400, Height: 10},
+		}}),
+		NewSegment(DrawableOptions{BodyOptions: BodyOptions{
+			StaticBody:     true,
+			SegmentOptions: SegmentOptions{A: vect.Vect{X: 0, Y: 0}, B: vect.Vect{X: 0, Y: 300}, Radius: 2},
+		}}),
+		NewSegment(DrawableOptions{BodyOptions: BodyOptions{
+			StaticBody:     true,
+			SegmentOptions: SegmentOptions{A: vect.Vect{X: 400, Y: 0}, B: vect.Vect{X: 400, Y: 300}, Radius: 2},
+		}}),
+	)
+	cup := NewBox(DrawableOptions{BodyOptions: BodyOptions{
+		Position:   vect.Vect{X: 200, Y: 30},
+		Mass:       10,
+		Friction:   friction,
+		BoxOptions: BoxOptions{Width: 60, Height: 20},
+	}})
+	w.Add(cup)
+	w.BindKey(pixel.KeyLeft, InputHeld, func(*World) { cup.GetBody().SetVelocity(-150, 0) })
+	w.BindKey(pixel.KeyRight, InputHeld, func(*World) { cup.GetBody().SetVelocity(150, 0) })
+
+	var steps int
+	w.StepCallback = func(vect.Float) {
+		steps++
+		if steps%spawnInterval != 0 {
+			return
+		}
+		w.Spawn(NewCircle(DrawableOptions{BodyOptions: BodyOptions{
+			Position:      vect.Vect{X: 20 + vect.Float(random.Intn(360)), Y: 280},
+			Angle:         vect.Float(random.Float32()),
+			Mass:          1,
+			Elasticity:    0.5,
+			Friction:      friction,
+			CircleOptions: CircleOptions{Radius: 8},
+		}}))
+	}
+	return w
+}
+
+func TestWorld_LoadReplay(t *testing.T) {
+	tests := []struct {
+		name          string
+		spawnInterval int
+		friction      vect.Float
+		wantErr       error
+	}{
+		{name: "same world", spawnInterval: 10, friction: 0.7},
+		{name: "different spawns", spawnInterval: 11, friction: 0.7, wantErr: ErrReplayDiverged},
+		{name: "different physics", spawnInterval: 10, friction: 0.2, wantErr: ErrReplayDiverged},
+	}
+
+	const steps = 400
+	w := catchWorld(1, 10, 0.7)
+	if err := w.StartRecording(); err != nil {
+		t.Fatal(err)
+	}
+	for step := range steps {
+		// move the cup left and right, with pauses in between
+		w.handleInput(fakeInput{pressed: map[pixel.Button]bool{
+			pixel.KeyLeft:  step%100 < 30,
+			pixel.KeyRight: step%100 >= 50 && step%100 < 80,
+		}})
+		w.Step(w.physicsDt())
+	}
+	var recording bytes.Buffer
+	if err := w.SaveReplay(&recording); err != nil {
+		t.Fatal(err)
+	}
+	if len(w.replay.Inputs) == 0 || len(w.replay.Spawns) == 0 {
+		t.Fatalf("recording: got %d inputs and %d spawns", len(w.replay.Inputs), len(w.replay.Spawns))
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// a different seed spawns the balls elsewhere, unless the replay moves them to their recorded position
+			replayed := catchWorld(2, tt.spawnInterval, tt.friction)
+			if err := replayed.LoadReplay(bytes.NewReader(recording.Bytes())); err != nil {
+				t.Fatal(err)
+			}
+			if err := replayed.RunHeadless(context.Background(), 0); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("RunHeadless: got %v, want %v", err, tt.wantErr)
+			}
+			if !errors.Is(replayed.ReplayErr(), tt.wantErr) {
+				t.Errorf("ReplayErr: got %v, want %v", replayed.ReplayErr(), tt.wantErr)
+			}
+			if replayed.steps != steps {
+				t.Errorf("steps: got %d, want %d", replayed.steps, steps)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package pixelmunk
+
+import "math"
+
+// snapshotRing holds the most recent Snapshots, up to its capacity
+type snapshotRing struct {
+	snapshots []Snapshot
+	next      int
+	count     int
+}
+
+// push adds a Snapshot, dropping the oldest one if the ring is full. Changing the capacity clears the ring.
+func (r *snapshotRing) push(s Snapshot, capacity int) {
+	if len(r.snapshots) != capacity {
+		r.snapshots = make([]Snapshot, capacity)
+		r.next, r.count = 0, 0
+	}
+	r.snapshots[r.next] = s
+	r.next = (r.next + 1) % capacity
+	r.count = min(r.count+1, capacity)
+}
+
+// pop removes and returns the most recent Snapshot
+func (r *snapshotRing) pop() (Snapshot, bool) {
+	if r.count == 0 {
+		return Snapshot{}, false
+	}
+	r.next = (r.next - 1 + len(r.snapshots)) % len(r.snapshots)
+	r.count--
+	s := r.snapshots[r.next]
+	// don't keep the removed Objects of an old Snapshot alive
+	r.snapshots[r.next] = Snapshot{}
+	return s, true
+}
+
+func (r *snapshotRing) clear() {
+	r.snapshots = nil
+	r.next, r.count = 0, 0
+}
+
+// Rewind runs the simulation backwards in the current frame of the default run loop, at the speed at which it
+// normally runs forward, until the start of the history kept by RewindSeconds. Call it from a key binding with
+// InputHeld, to rewind for as long as the key is held down. Holding Backspace does this too, unless
+// DisableDebugKeys is set. Rewind does nothing while recording or replaying.
+func (w *World) Rewind() {
+	w.rewinding = !w.recording() && !w.Replaying()
+}
+
+// recordHistory saves the state of the World before a step, so Rewind can return to it
+func (w *World) recordHistory() {
+	if w.RewindSeconds <= 0 {
+		w.history.clear()
+		return
+	}
+	capacity := int(math.Ceil(w.RewindSeconds / float64(w.physicsDt())))
+	w.history.push(w.Snapshot(), max(capacity, 1))
+}
+
+// rewindStep returns the World to its state before the last recorded step. It returns false if there is no
+// history left.
+func (w *World) rewindStep() bool {
+	s, ok := w.history.pop()
+	if ok {
+		w.saveBodyStates()
+		w.Restore(s)
+	}
+	return ok
+}
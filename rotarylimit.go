@@ -0,0 +1,107 @@
+package pixelmunk
+
+import (
+	"github.com/gopxl/pixel/v2/ext/imdraw"
+	"github.com/vova616/chipmunk"
+	"github.com/vova616/chipmunk/vect"
+)
+
+// RotaryLimit keeps the relative angle of two bodies between a minimum and a maximum
+type RotaryLimit struct {
+	baseConstraint
+	limit *rotaryLimitConstraint
+}
+
+var _ Drawable = &RotaryLimit{}
+
+// NewRotaryLimit creates a RotaryLimit between two Objects. The angle of b minus the angle of a is kept between
+// min and max.
+func NewRotaryLimit(a, b *Object, min, max vect.Float, options DrawableOptions) *RotaryLimit {
+	limit := newRotaryLimitConstraint(a.GetBody(), b.GetBody(), min, max)
+	return &RotaryLimit{
+		baseConstraint: baseConstraint{constraint: limit, options: options},
+		limit:          limit,
+	}
+}
+
+// Draw draws the RotaryLimit on the provided imdraw.IMDraw as an arc around the second body, covering the allowed
+// angles, with a spoke for the current angle of the second body
+func (l RotaryLimit) Draw(imd *imdraw.IMDraw) {
+	l.drawPosed(imd, currentState)
+}
+
+// drawPosed draws the RotaryLimit with its bodies at the position and angle returned by pose
+func (l RotaryLimit) drawPosed(imd *imdraw.IMDraw, pose poseFunc) {
+	if l.options.JointOptions.Draw {
+		bodyA, bodyB := l.bodies()
+		poseA, poseB := pose(bodyA), pose(bodyB)
+		centre := toPixel(poseB.position)
+
+		imd.Color = l.options.Color
+		drawArc(imd, centre, rotaryRadius, float64(poseA.angle+l.limit.min), float64(poseA.angle+l.limit.max), l.options.Thickness)
+		drawSpoke(imd, centre, rotaryRadius, float64(poseB.angle), l.options.Thickness)
+	}
+}
+
+type rotaryLimitConstraint struct {
+	chipmunk.BasicConstraint
+	min, max vect.Float
+
+	iSum, bias vect.Float
+	jAcc, jMax vect.Float
+}
+
+func newRotaryLimitConstraint(a, b *chipmunk.Body, min, max vect.Float) *rotaryLimitConstraint {
+	return &rotaryLimitConstraint{
+		BasicConstraint: chipmunk.NewConstraint(a, b),
+		min:             min,
+		max:             max,
+	}
+}
+
+func (c *rotaryLimitConstraint) PreStep(dt vect.Float) {
+	a, b := c.BodyA, c.BodyB
+
+	dist := b.Angle() - a.Angle()
+	var pdist vect.Float
+	if dist > c.max {
+		pdist = c.max - dist
+	} else if dist < c.min {
+		pdist = c.min - dist
+	}
+
+	c.iSum = 1 / (invMoment(a) + invMoment(b))
+	c.bias = clampFloat(-biasCoef(c.ErrorBias, dt)*pdist/dt, -c.MaxBias, c.MaxBias)
+	c.jMax = c.MaxForce * dt
+
+	// not at a limit: nothing to carry over to the next step
+	if c.bias == 0 {
+		c.jAcc = 0
+	}
+}
+
+func (c *rotaryLimitConstraint) ApplyCachedImpulse(dtCoef vect.Float) {
+	applyAngularImpulses(c.BodyA, c.BodyB, c.jAcc*dtCoef)
+}
+
+func (c *rotaryLimitConstraint) ApplyImpulse() {
+	if c.bias == 0 {
+		return
+	}
+	a, b := c.BodyA, c.BodyB
+
+	wr := angularVelocity(b) - angularVelocity(a)
+	j := -(c.bias + wr) * c.iSum
+	jOld := c.jAcc
+	if c.bias < 0 {
+		c.jAcc = clampFloat(jOld+j, 0, c.jMax)
+	} else {
+		c.jAcc = clampFloat(jOld+j, -c.jMax, 0)
+	}
+
+	applyAngularImpulses(a, b, c.jAcc-jOld)
+}
+
+func (c *rotaryLimitConstraint) Impulse() vect.Float {
+	return absFloat(c.jAcc)
+}
@@ -0,0 +1,102 @@
+package pixelmunk
+
+import (
+	"github.com/gopxl/pixel/v2/ext/imdraw"
+	"github.com/vova616/chipmunk"
+	"github.com/vova616/chipmunk/vect"
+	"math"
+)
+
+// RotarySpring is a damped spring that works on the relative angle of two bodies, rather than on their distance
+type RotarySpring struct {
+	baseConstraint
+	spring *rotarySpringConstraint
+}
+
+var _ Drawable = &RotarySpring{}
+
+// NewRotarySpring creates a damped RotarySpring between two Objects. The spring turns the bodies towards restAngle,
+// the angle of a minus the angle of b, with a torque proportional to stiffness. damping slows down their relative
+// rotation.
+func NewRotarySpring(a, b *Object, restAngle, stiffness, damping vect.Float, options DrawableOptions) *RotarySpring {
+	spring := newRotarySpringConstraint(a.GetBody(), b.GetBody(), restAngle, stiffness, damping)
+	return &RotarySpring{
+		baseConstraint: baseConstraint{constraint: spring, options: options},
+		spring:         spring,
+	}
+}
+
+// Draw draws the RotarySpring on the provided imdraw.IMDraw as an arc around the second body, showing how far
+// the spring is wound up
+func (s RotarySpring) Draw(imd *imdraw.IMDraw) {
+	s.drawPosed(imd, currentState)
+}
+
+// drawPosed draws the RotarySpring with its bodies at the position and angle returned by pose
+func (s RotarySpring) drawPosed(imd *imdraw.IMDraw, pose poseFunc) {
+	if s.options.JointOptions.Draw {
+		bodyA, bodyB := s.bodies()
+		poseA, poseB := pose(bodyA), pose(bodyB)
+		centre := toPixel(poseB.position)
+		rest := float64(poseA.angle - s.spring.restAngle)
+
+		imd.Color = s.options.Color
+		drawSpoke(imd, centre, rotaryRadius, rest, s.options.Thickness)
+		drawArc(imd, centre, rotaryRadius, rest, float64(poseB.angle), s.options.Thickness)
+	}
+}
+
+type rotarySpringConstraint struct {
+	chipmunk.BasicConstraint
+	restAngle vect.Float
+	stiffness vect.Float
+	damping   vect.Float
+
+	iSum      vect.Float
+	targetWRN vect.Float
+	wCoef     vect.Float
+	jAcc      vect.Float
+}
+
+func newRotarySpringConstraint(a, b *chipmunk.Body, restAngle, stiffness, damping vect.Float) *rotarySpringConstraint {
+	return &rotarySpringConstraint{
+		BasicConstraint: chipmunk.NewConstraint(a, b),
+		restAngle:       restAngle,
+		stiffness:       stiffness,
+		damping:         damping,
+	}
+}
+
+func (c *rotarySpringConstraint) PreStep(dt vect.Float) {
+	a, b := c.BodyA, c.BodyB
+
+	moment := invMoment(a) + invMoment(b)
+	c.iSum = 1 / moment
+
+	c.wCoef = 1 - vect.Float(math.Exp(float64(-c.damping*dt*moment)))
+	c.targetWRN = 0
+
+	// the spring torque is applied here; ApplyImpulse only handles the damping
+	jSpring := (a.Angle() - b.Angle() - c.restAngle) * c.stiffness * dt
+	c.jAcc = jSpring
+	applyAngularImpulses(a, b, jSpring)
+}
+
+func (c *rotarySpringConstraint) ApplyCachedImpulse(_ vect.Float) {
+}
+
+func (c *rotarySpringConstraint) ApplyImpulse() {
+	a, b := c.BodyA, c.BodyB
+
+	wrn := angularVelocity(a) - angularVelocity(b)
+	wDamp := (c.targetWRN - wrn) * c.wCoef
+	c.targetWRN = wrn + wDamp
+
+	jDamp := wDamp * c.iSum
+	c.jAcc += jDamp
+	applyAngularImpulses(a, b, -jDamp)
+}
+
+func (c *rotarySpringConstraint) Impulse() vect.Float {
+	return absFloat(c.jAcc)
+}
@@ -0,0 +1,718 @@
+package pixelmunk
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/vova616/chipmunk"
+	"github.com/vova616/chipmunk/vect"
+	"golang.org/x/image/colornames"
+	"gopkg.in/yaml.v3"
+	"image/color"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// sceneVersion is the version of the scene format written by Save. LoadWorld rejects scenes of any other version.
+const sceneVersion = 1
+
+// A scene is the serialized form of a World. Objects are referenced by joints through their ID. The format only
+// holds what can be rebuilt by LoadWorld: callbacks, CustomDrawFuncs and the type of custom Drawables are not saved.
+type scene struct {
+	Version int           `json:"version" yaml:"version"`
+	World   sceneWorld    `json:"world" yaml:"world"`
+	Objects []sceneObject `json:"objects,omitempty" yaml:"objects,omitempty"`
+	Joints  []sceneJoint  `json:"joints,omitempty" yaml:"joints,omitempty"`
+}
+
+type sceneWorld struct {
+	Name       string    `json:"name" yaml:"name"`
+	Bounds     sceneRect `json:"bounds" yaml:"bounds"`
+	FrameRate  int       `json:"frameRate,omitempty" yaml:"frameRate,omitempty"`
+	PhysicsHz  int       `json:"physicsHz,omitempty" yaml:"physicsHz,omitempty"`
+	Substeps   int       `json:"substeps,omitempty" yaml:"substeps,omitempty"`
+	MaxCatchUp int       `json:"maxCatchUp,omitempty" yaml:"maxCatchUp,omitempty"`
+	Iterations int       `json:"iterations,omitempty" yaml:"iterations,omitempty"`
+	Gravity    sceneVec  `json:"gravity" yaml:"gravity"`
+}
+
+type sceneVec struct {
+	X vect.Float `json:"x" yaml:"x"`
+	Y vect.Float `json:"y" yaml:"y"`
+}
+
+type sceneRect struct {
+	Min sceneVec `json:"min" yaml:"min"`
+	Max sceneVec `json:"max" yaml:"max"`
+}
+
+type sceneObject struct {
+	ID   int    `json:"id" yaml:"id"`
+	Kind string `json:"kind" yaml:"kind"`
+	// Hidden objects aren't part of the World's Objects, but are attached to joints
+	Hidden        bool      `json:"hidden,omitempty" yaml:"hidden,omitempty"`
+	Color         string    `json:"color,omitempty" yaml:"color,omitempty"`
+	Thickness     float64   `json:"thickness,omitempty" yaml:"thickness,omitempty"`
+	Body          sceneBody `json:"body" yaml:"body"`
+	sceneGeometry `yaml:",inline"`
+	// Shapes of a compound object
+	Shapes []sceneShape `json:"shapes,omitempty" yaml:"shapes,omitempty"`
+	// Points of a terrain
+	Points []sceneVec `json:"points,omitempty" yaml:"points,omitempty"`
+}
+
+type sceneBody struct {
+	Static            bool           `json:"static,omitempty" yaml:"static,omitempty"`
+	Position          sceneVec       `json:"position" yaml:"position"`
+	Angle             vect.Float     `json:"angle,omitempty" yaml:"angle,omitempty"`
+	Mass              vect.Float     `json:"mass,omitempty" yaml:"mass,omitempty"`
+	Velocity          sceneVec       `json:"velocity" yaml:"velocity"`
+	AngularVelocity   vect.Float     `json:"angularVelocity,omitempty" yaml:"angularVelocity,omitempty"`
+	IgnoreGravity     bool           `json:"ignoreGravity,omitempty" yaml:"ignoreGravity,omitempty"`
+	Elasticity        vect.Float     `json:"elasticity,omitempty" yaml:"elasticity,omitempty"`
+	Friction          vect.Float     `json:"friction,omitempty" yaml:"friction,omitempty"`
+	Sensor            bool           `json:"sensor,omitempty" yaml:"sensor,omitempty"`
+	CollisionType     CollisionType  `json:"collisionType,omitempty" yaml:"collisionType,omitempty"`
+	CollisionGroup    chipmunk.Group `json:"collisionGroup,omitempty" yaml:"collisionGroup,omitempty"`
+	CollisionCategory uint32         `json:"collisionCategory,omitempty" yaml:"collisionCategory,omitempty"`
+	CollisionMask     uint32         `json:"collisionMask,omitempty" yaml:"collisionMask,omitempty"`
+}
+
+// sceneGeometry holds the size of a shape. Only the fields for the shape's type are set.
+type sceneGeometry struct {
+	Radius   vect.Float `json:"radius,omitempty" yaml:"radius,omitempty"`
+	Width    vect.Float `json:"width,omitempty" yaml:"width,omitempty"`
+	Height   vect.Float `json:"height,omitempty" yaml:"height,omitempty"`
+	Vertices []sceneVec `json:"vertices,omitempty" yaml:"vertices,omitempty"`
+	A        *sceneVec  `json:"a,omitempty" yaml:"a,omitempty"`
+	B        *sceneVec  `json:"b,omitempty" yaml:"b,omitempty"`
+}
+
+type sceneShape struct {
+	Type          string     `json:"type" yaml:"type"`
+	Offset        sceneVec   `json:"offset" yaml:"offset"`
+	Mass          vect.Float `json:"mass,omitempty" yaml:"mass,omitempty"`
+	Elasticity    vect.Float `json:"elasticity,omitempty" yaml:"elasticity,omitempty"`
+	Friction      vect.Float `json:"friction,omitempty" yaml:"friction,omitempty"`
+	Color         string     `json:"color,omitempty" yaml:"color,omitempty"`
+	Sensor        bool       `json:"sensor,omitempty" yaml:"sensor,omitempty"`
+	sceneGeometry `yaml:",inline"`
+}
+
+type sceneJoint struct {
+	Type    string    `json:"type" yaml:"type"`
+	A       int       `json:"a" yaml:"a"`
+	B       int       `json:"b" yaml:"b"`
+	AnchorA *sceneVec `json:"anchorA,omitempty" yaml:"anchorA,omitempty"`
+	AnchorB *sceneVec `json:"anchorB,omitempty" yaml:"anchorB,omitempty"`
+	GrooveA *sceneVec `json:"grooveA,omitempty" yaml:"grooveA,omitempty"`
+	GrooveB *sceneVec `json:"grooveB,omitempty" yaml:"grooveB,omitempty"`
+	// Distance of a pin joint. Defaults to the distance between the anchors when the scene is loaded.
+	Distance   *vect.Float `json:"distance,omitempty" yaml:"distance,omitempty"`
+	Min        vect.Float  `json:"min,omitempty" yaml:"min,omitempty"`
+	Max        vect.Float  `json:"max,omitempty" yaml:"max,omitempty"`
+	RestLength vect.Float  `json:"restLength,omitempty" yaml:"restLength,omitempty"`
+	RestAngle  vect.Float  `json:"restAngle,omitempty" yaml:"restAngle,omitempty"`
+	Stiffness  vect.Float  `json:"stiffness,omitempty" yaml:"stiffness,omitempty"`
+	Damping    vect.Float  `json:"damping,omitempty" yaml:"damping,omitempty"`
+	Phase      vect.Float  `json:"phase,omitempty" yaml:"phase,omitempty"`
+	Ratchet    vect.Float  `json:"ratchet,omitempty" yaml:"ratchet,omitempty"`
+	// Angle of a ratchet. Defaults to the angle between the bodies when the scene is loaded.
+	Angle        *vect.Float `json:"angle,omitempty" yaml:"angle,omitempty"`
+	Ratio        vect.Float  `json:"ratio,omitempty" yaml:"ratio,omitempty"`
+	Rate         vect.Float  `json:"rate,omitempty" yaml:"rate,omitempty"`
+	MaxForce     vect.Float  `json:"maxForce,omitempty" yaml:"maxForce,omitempty"`
+	Color        string      `json:"color,omitempty" yaml:"color,omitempty"`
+	Thickness    float64     `json:"thickness,omitempty" yaml:"thickness,omitempty"`
+	Draw         bool        `json:"draw,omitempty" yaml:"draw,omitempty"`
+	Coils        int         `json:"coils,omitempty" yaml:"coils,omitempty"`
+	BreakImpulse vect.Float  `json:"breakImpulse,omitempty" yaml:"breakImpulse,omitempty"`
+}
+
+// Save writes the World, its Objects and joints as a JSON scene, which LoadWorld can read back. Objects and joints
+// are saved in their current state (position, velocity, etc.).
+//
+// Callbacks, key bindings and CustomDrawFuncs can't be saved. Custom Drawables that embed an Object are saved as
+// that Object.
+func (w *World) Save(out io.Writer) error {
+	s, err := w.scene()
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(s)
+}
+
+// SaveYAML writes the World as a YAML scene. See Save.
+func (w *World) SaveYAML(out io.Writer) error {
+	s, err := w.scene()
+	if err != nil {
+		return err
+	}
+	encoder := yaml.NewEncoder(out)
+	encoder.SetIndent(2)
+	if err = encoder.Encode(s); err == nil {
+		err = encoder.Close()
+	}
+	return err
+}
+
+// LoadWorld creates a new World from a JSON or YAML scene, as written by Save or SaveYAML. Colors can be written
+// as "#rrggbb", "#rrggbbaa" or as a name from golang.org/x/image/colornames.
+func LoadWorld(in io.Reader) (*World, error) {
+	var s scene
+	// YAML is a superset of JSON, so this reads both
+	decoder := yaml.NewDecoder(in)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&s); err != nil {
+		return nil, fmt.Errorf("scene: %w", err)
+	}
+	if s.Version != sceneVersion {
+		return nil, fmt.Errorf("scene: unsupported version %d", s.Version)
+	}
+	return s.world()
+}
+
+// scene returns the serialized form of the World
+func (w *World) scene() (scene, error) {
+	s := scene{
+		Version: sceneVersion,
+		World: sceneWorld{
+			Name:       w.Name,
+			Bounds:     sceneRect{Min: toSceneVec(toVect(w.Bounds.Min)), Max: toSceneVec(toVect(w.Bounds.Max))},
+			FrameRate:  w.FrameRate,
+			PhysicsHz:  w.PhysicsHz,
+			Substeps:   w.Substeps,
+			MaxCatchUp: w.MaxCatchUp,
+			Iterations: w.Space.Iterations,
+			Gravity:    toSceneVec(w.Space.Gravity),
+		},
+	}
+
+	ids := make(map[*chipmunk.Body]int)
+	addObject := func(drawable Drawable, hidden bool) (int, error) {
+		body := drawable.GetBody()
+		if id, ok := ids[body]; ok {
+			return id, nil
+		}
+		if !body.IsStatic() && len(body.Shapes) == 0 {
+			// LoadWorld can't rebuild it: chipmunk needs the shapes for the body's moment of inertia
+			return 0, errors.New("scene: dynamic body without shapes")
+		}
+		object := w.bodyObjects[body]
+		if object == nil {
+			// a body that isn't part of the World, or whose Drawable isn't built on an Object
+			object = &Object{body: body}
+		}
+		o := sceneObjectOf(object)
+		if terrain, ok := drawable.(*Terrain); ok {
+			o.Kind = "terrain"
+			o.sceneGeometry = sceneGeometry{Radius: terrain.options.BodyOptions.SegmentOptions.Radius}
+			o.Shapes = nil
+			o.Points = toSceneVecs(terrain.points)
+		}
+		o.ID = len(s.Objects) + 1
+		o.Hidden = hidden
+		ids[body] = o.ID
+		s.Objects = append(s.Objects, o)
+		return o.ID, nil
+	}
+
+	for _, drawable := range w.Objects {
+		if drawable.GetType() == DrawableBody {
+			if _, err := addObject(drawable, false); err != nil {
+				return s, err
+			}
+		}
+	}
+	for _, drawable := range w.Objects {
+		if drawable.GetType() != DrawableJoint {
+			continue
+		}
+		j, err := sceneJointOf(drawable)
+		if err != nil {
+			return s, err
+		}
+		// joints may be attached to bodies that aren't part of the World, e.g. an invisible static anchor
+		c := drawable.GetConstraint().Constraint()
+		if j.A, err = addObject(&Object{body: c.BodyA}, true); err != nil {
+			return s, err
+		}
+		if j.B, err = addObject(&Object{body: c.BodyB}, true); err != nil {
+			return s, err
+		}
+		s.Joints = append(s.Joints, j)
+	}
+	return s, nil
+}
+
+// sceneObjectOf returns the serialized form of an Object. Objects with a single, centred shape are saved by the
+// shape's kind; all others are saved as compound objects.
+func sceneObjectOf(o *Object) sceneObject {
+	body := o.GetBody()
+	options := o.options.BodyOptions
+	velocity := body.Velocity()
+	mass := options.Mass
+	if !body.IsStatic() {
+		mass = body.Mass()
+	}
+	s := sceneObject{
+		Color:     colorString(o.options.Color),
+		Thickness: o.options.Thickness,
+		Body: sceneBody{
+			Static:            body.IsStatic(),
+			Position:          toSceneVec(body.Position()),
+			Angle:             body.Angle(),
+			Mass:              mass,
+			Velocity:          toSceneVec(velocity),
+			AngularVelocity:   angularVelocity(body),
+			IgnoreGravity:     body.IgnoreGravity && !body.IsStatic(),
+			Elasticity:        options.Elasticity,
+			Friction:          options.Friction,
+			Sensor:            options.Sensor,
+			CollisionType:     options.CollisionType,
+			CollisionGroup:    options.CollisionGroup,
+			CollisionCategory: options.CollisionCategory,
+			CollisionMask:     options.CollisionMask,
+		},
+	}
+
+	if len(body.Shapes) == 1 {
+		if _, compound := body.Shapes[0].UserData.(ShapeSpec); !compound {
+			if kind, geometry, offset := shapeGeometry(body.Shapes[0]); offset == (vect.Vect{}) {
+				s.Kind, s.sceneGeometry = kind, geometry
+				return s
+			}
+		}
+	}
+
+	s.Kind = "compound"
+	for _, shape := range body.Shapes {
+		spec, ok := shape.UserData.(ShapeSpec)
+		if !ok {
+			// not created by NewCompound: the mass is divided evenly over the shapes when loading
+			spec = ShapeSpec{Elasticity: options.Elasticity, Friction: options.Friction, Sensor: shape.IsSensor}
+		}
+		kind, geometry, offset := shapeGeometry(shape)
+		s.Shapes = append(s.Shapes, sceneShape{
+			Type:          kind,
+			Offset:        toSceneVec(offset),
+			Mass:          spec.Mass,
+			Elasticity:    spec.Elasticity,
+			Friction:      spec.Friction,
+			Color:         colorString(spec.Color),
+			Sensor:        spec.Sensor,
+			sceneGeometry: geometry,
+		})
+	}
+	return s
+}
+
+// shapeGeometry returns the kind, size and offset of a shape, relative to its body
+func shapeGeometry(shape *chipmunk.Shape) (string, sceneGeometry, vect.Vect) {
+	switch shape.ShapeType() {
+	case chipmunk.ShapeType_Circle:
+		circle := shape.GetAsCircle()
+		return "circle", sceneGeometry{Radius: circle.Radius}, circle.Position
+	case chipmunk.ShapeType_Box:
+		box := shape.GetAsBox()
+		return "box", sceneGeometry{Width: box.Width, Height: box.Height}, box.Position
+	case chipmunk.ShapeType_Polygon:
+		return "polygon", sceneGeometry{Vertices: toSceneVecs(shape.GetAsPolygon().Verts)}, vect.Vect{}
+	default:
+		segment := shape.GetAsSegment()
+		a, b := toSceneVec(segment.A), toSceneVec(segment.B)
+		return "segment", sceneGeometry{Radius: segment.Radius, A: &a, B: &b}, vect.Vect{}
+	}
+}
+
+// sceneJointOf returns the serialized form of a joint. The IDs of the bodies are set by the caller.
+func sceneJointOf(drawable Drawable) (sceneJoint, error) {
+	options := drawable.GetOptions()
+	s := sceneJoint{
+		Color:        colorString(options.Color),
+		Thickness:    options.Thickness,
+		Draw:         options.JointOptions.Draw,
+		Coils:        options.JointOptions.Coils,
+		BreakImpulse: options.JointOptions.BreakImpulse,
+	}
+	if maxForce := drawable.GetConstraint().Constraint().MaxForce; !isInfinite(maxForce) {
+		s.MaxForce = maxForce
+	}
+
+	switch j := drawable.(type) {
+	case *Joint:
+		s.Type = "pivot"
+		s.AnchorA, s.AnchorB = toSceneVecPtr(j.origOffsetA), toSceneVecPtr(j.origOffsetB)
+	case *PinJoint:
+		s.Type = "pin"
+		s.AnchorA, s.AnchorB = toSceneVecPtr(j.pin.anchorA), toSceneVecPtr(j.pin.anchorB)
+		distance := j.pin.dist
+		s.Distance = &distance
+	case *SlideJoint:
+		s.Type = "slide"
+		s.AnchorA, s.AnchorB = toSceneVecPtr(j.slide.anchorA), toSceneVecPtr(j.slide.anchorB)
+		s.Min, s.Max = j.slide.min, j.slide.max
+	case *GrooveJoint:
+		s.Type = "groove"
+		s.GrooveA, s.GrooveB = toSceneVecPtr(j.groove.grooveA), toSceneVecPtr(j.groove.grooveB)
+		s.AnchorB = toSceneVecPtr(j.groove.anchorB)
+	case *Spring:
+		s.Type = "spring"
+		s.AnchorA, s.AnchorB = toSceneVecPtr(j.spring.anchorA), toSceneVecPtr(j.spring.anchorB)
+		s.RestLength, s.Stiffness, s.Damping = j.spring.restLength, j.spring.stiffness, j.spring.damping
+	case *RotarySpring:
+		s.Type = "rotarySpring"
+		s.RestAngle, s.Stiffness, s.Damping = j.spring.restAngle, j.spring.stiffness, j.spring.damping
+	case *RotaryLimit:
+		s.Type = "rotaryLimit"
+		s.Min, s.Max = j.limit.min, j.limit.max
+	case *Ratchet:
+		s.Type = "ratchet"
+		s.Phase, s.Ratchet = j.ratchet.phase, j.ratchet.ratchet
+		angle := j.ratchet.angle
+		s.Angle = &angle
+	case *Gear:
+		s.Type = "gear"
+		s.Phase, s.Ratio = j.gear.phase, j.gear.ratio
+	case *Motor:
+		s.Type = "motor"
+		s.Rate = j.motor.rate
+	default:
+		return s, fmt.Errorf("scene: unsupported joint %T", drawable)
+	}
+	return s, nil
+}
+
+// world creates the World described by the scene
+func (s scene) world() (*World, error) {
+	b := s.World.Bounds
+	w := NewWorld(s.World.Name, float64(b.Min.X), float64(b.Min.Y), float64(b.Max.X), float64(b.Max.Y))
+	if s.World.FrameRate > 0 {
+		w.FrameRate = s.World.FrameRate
+	}
+	w.PhysicsHz = s.World.PhysicsHz
+	w.Substeps = s.World.Substeps
+	w.MaxCatchUp = s.World.MaxCatchUp
+	if s.World.Iterations > 0 {
+		w.Space.Iterations = s.World.Iterations
+	}
+	w.Space.Gravity = s.World.Gravity.vect()
+
+	objects := make(map[int]*Object)
+	for _, o := range s.Objects {
+		if _, ok := objects[o.ID]; ok {
+			return nil, fmt.Errorf("scene: duplicate object id %d", o.ID)
+		}
+		drawable, object, err := o.object()
+		if err != nil {
+			return nil, fmt.Errorf("scene: object %d: %w", o.ID, err)
+		}
+		objects[o.ID] = object
+		if !o.Hidden {
+			w.Add(drawable)
+		}
+	}
+
+	for i, j := range s.Joints {
+		a, b := objects[j.A], objects[j.B]
+		if a == nil || b == nil {
+			return nil, fmt.Errorf("scene: joint %d: unknown object", i+1)
+		}
+		joint, err := j.joint(a, b)
+		if err != nil {
+			return nil, fmt.Errorf("scene: joint %d: %w", i+1, err)
+		}
+		w.Add(joint)
+	}
+	return w, nil
+}
+
+// object creates the Object described by the sceneObject. For terrain, the Drawable is the Terrain that
+// embeds the Object.
+func (o sceneObject) object() (Drawable, *Object, error) {
+	c, err := parseColor(o.Color)
+	if err != nil {
+		return nil, nil, err
+	}
+	options := DrawableOptions{
+		Color:     c,
+		Thickness: o.Thickness,
+		BodyOptions: BodyOptions{
+			StaticBody:        o.Body.Static,
+			Position:          o.Body.Position.vect(),
+			Angle:             o.Body.Angle,
+			Mass:              o.Body.Mass,
+			Velocity:          o.Body.Velocity.vect(),
+			Elasticity:        o.Body.Elasticity,
+			Friction:          o.Body.Friction,
+			Sensor:            o.Body.Sensor,
+			CollisionType:     o.Body.CollisionType,
+			CollisionGroup:    o.Body.CollisionGroup,
+			CollisionCategory: o.Body.CollisionCategory,
+			CollisionMask:     o.Body.CollisionMask,
+		},
+	}
+	o.sceneGeometry.apply(&options.BodyOptions)
+	// chipmunk panics on a dynamic body without mass
+	mass := o.Body.Mass
+	for _, shape := range o.Shapes {
+		mass += shape.Mass
+	}
+	if !o.Body.Static && o.Kind != "terrain" && mass <= 0 {
+		return nil, nil, errors.New("dynamic object without mass")
+	}
+	if err = o.sceneGeometry.validate(o.Kind); err != nil {
+		return nil, nil, err
+	}
+
+	var drawable Drawable
+	var object *Object
+	switch o.Kind {
+	case "circle":
+		object = NewCircle(options)
+	case "box":
+		object = NewBox(options)
+	case "polygon":
+		if object, err = NewPolygon(options); err != nil {
+			return nil, nil, err
+		}
+	case "segment":
+		object = NewSegment(options)
+	case "compound":
+		var shapes []ShapeSpec
+		for _, shape := range o.Shapes {
+			spec, err := shape.spec()
+			if err != nil {
+				return nil, nil, err
+			}
+			shapes = append(shapes, spec)
+		}
+		if object, err = NewCompound(options, shapes...); err != nil {
+			return nil, nil, err
+		}
+	case "terrain":
+		terrain, err := NewTerrain(fromSceneVecs(o.Points), options)
+		if err != nil {
+			return nil, nil, err
+		}
+		drawable, object = terrain, terrain.Object
+	default:
+		return nil, nil, fmt.Errorf("unsupported kind %q", o.Kind)
+	}
+	if drawable == nil {
+		drawable = object
+	}
+
+	body := object.GetBody()
+	body.SetAngularVelocity(float32(o.Body.AngularVelocity))
+	if o.Body.IgnoreGravity {
+		body.IgnoreGravity = true
+	}
+	return drawable, object, nil
+}
+
+// apply sets the shape-specific options
+func (g sceneGeometry) apply(options *BodyOptions) {
+	options.CircleOptions.Radius = float32(g.Radius)
+	options.BoxOptions = BoxOptions{Width: g.Width, Height: g.Height}
+	options.PolygonOptions.Vertices = fromSceneVecs(g.Vertices)
+	options.SegmentOptions.Radius = g.Radius
+	if g.A != nil {
+		options.SegmentOptions.A = g.A.vect()
+	}
+	if g.B != nil {
+		options.SegmentOptions.B = g.B.vect()
+	}
+}
+
+// validate returns an error if the geometry can't be built into a shape of the given kind. chipmunk panics on
+// shapes without area or length; polygons are validated when they're built.
+func (g sceneGeometry) validate(kind string) error {
+	switch kind {
+	case "circle":
+		if g.Radius <= 0 {
+			return fmt.Errorf("circle: radius must be positive, got %v", g.Radius)
+		}
+	case "box":
+		if g.Width <= 0 || g.Height <= 0 {
+			return fmt.Errorf("box: width and height must be positive, got %v and %v", g.Width, g.Height)
+		}
+	case "segment":
+		if g.A.vectOrZero() == g.B.vectOrZero() {
+			return errors.New("segment: a and b must be different points")
+		}
+	}
+	return nil
+}
+
+func (s sceneShape) spec() (ShapeSpec, error) {
+	c, err := parseColor(s.Color)
+	if err != nil {
+		return ShapeSpec{}, err
+	}
+	spec := ShapeSpec{
+		Offset:     s.Offset.vect(),
+		Mass:       s.Mass,
+		Elasticity: s.Elasticity,
+		Friction:   s.Friction,
+		Color:      c,
+		Sensor:     s.Sensor,
+	}
+	switch s.Type {
+	case "circle":
+		spec.Type = chipmunk.ShapeType_Circle
+	case "box":
+		spec.Type = chipmunk.ShapeType_Box
+	case "polygon":
+		spec.Type = chipmunk.ShapeType_Polygon
+	case "segment":
+		spec.Type = chipmunk.ShapeType_Segment
+	default:
+		return spec, fmt.Errorf("unsupported shape type %q", s.Type)
+	}
+	if err = s.sceneGeometry.validate(s.Type); err != nil {
+		return spec, err
+	}
+	var options BodyOptions
+	s.sceneGeometry.apply(&options)
+	spec.CircleOptions, spec.BoxOptions = options.CircleOptions, options.BoxOptions
+	spec.PolygonOptions, spec.SegmentOptions = options.PolygonOptions, options.SegmentOptions
+	return spec, nil
+}
+
+// joint creates the joint described by the sceneJoint, between Objects a and b
+func (j sceneJoint) joint(a, b *Object) (Drawable, error) {
+	c, err := parseColor(j.Color)
+	if err != nil {
+		return nil, err
+	}
+	options := DrawableOptions{
+		Color:     c,
+		Thickness: j.Thickness,
+		JointOptions: JointOptions{
+			Draw:         j.Draw,
+			Coils:        j.Coils,
+			BreakImpulse: j.BreakImpulse,
+		},
+	}
+	anchorA, anchorB := j.AnchorA.vectOrZero(), j.AnchorB.vectOrZero()
+
+	var joint Drawable
+	switch j.Type {
+	case "pivot":
+		joint = NewJointWithAnchor(a, b, anchorA, anchorB, options)
+	case "pin":
+		pin := NewPinJoint(a, b, anchorA, anchorB, options)
+		if j.Distance != nil {
+			pin.pin.dist = *j.Distance
+		}
+		joint = pin
+	case "slide":
+		joint = NewSlideJoint(a, b, anchorA, anchorB, j.Min, j.Max, options)
+	case "groove":
+		joint = NewGrooveJoint(a, b, j.GrooveA.vectOrZero(), j.GrooveB.vectOrZero(), anchorB, options)
+	case "spring":
+		joint = NewSpring(a, b, anchorA, anchorB, j.RestLength, j.Stiffness, j.Damping, options)
+	case "rotarySpring":
+		joint = NewRotarySpring(a, b, j.RestAngle, j.Stiffness, j.Damping, options)
+	case "rotaryLimit":
+		joint = NewRotaryLimit(a, b, j.Min, j.Max, options)
+	case "ratchet":
+		if j.Ratchet == 0 {
+			return nil, errors.New("ratchet must not be zero")
+		}
+		ratchet := NewRatchet(a, b, j.Phase, j.Ratchet, options)
+		if j.Angle != nil {
+			ratchet.ratchet.angle = *j.Angle
+		}
+		joint = ratchet
+	case "gear":
+		if j.Ratio == 0 {
+			return nil, errors.New("ratio must not be zero")
+		}
+		joint = NewGear(a, b, j.Phase, j.Ratio, options)
+	case "motor":
+		joint = NewMotor(a, b, j.Rate, options)
+	default:
+		return nil, fmt.Errorf("unsupported joint type %q", j.Type)
+	}
+	if j.MaxForce > 0 {
+		joint.GetConstraint().Constraint().MaxForce = j.MaxForce
+	}
+	return joint, nil
+}
+
+func toSceneVec(v vect.Vect) sceneVec {
+	return sceneVec{X: v.X, Y: v.Y}
+}
+
+func toSceneVecPtr(v vect.Vect) *sceneVec {
+	s := toSceneVec(v)
+	return &s
+}
+
+func toSceneVecs(vs []vect.Vect) []sceneVec {
+	var result []sceneVec
+	for _, v := range vs {
+		result = append(result, toSceneVec(v))
+	}
+	return result
+}
+
+func fromSceneVecs(vs []sceneVec) []vect.Vect {
+	var result []vect.Vect
+	for _, v := range vs {
+		result = append(result, v.vect())
+	}
+	return result
+}
+
+func (v sceneVec) vect() vect.Vect {
+	return vect.Vect{X: v.X, Y: v.Y}
+}
+
+func (v *sceneVec) vectOrZero() vect.Vect {
+	if v == nil {
+		return vect.Vect{}
+	}
+	return v.vect()
+}
+
+// colorString returns the color as "#rrggbb", or "#rrggbbaa" if it's translucent
+func colorString(c color.Color) string {
+	if c == nil {
+		return ""
+	}
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	if n.A == math.MaxUint8 {
+		return fmt.Sprintf("#%02x%02x%02x", n.R, n.G, n.B)
+	}
+	return fmt.Sprintf("#%02x%02x%02x%02x", n.R, n.G, n.B, n.A)
+}
+
+// parseColor parses a color written as "#rrggbb", "#rrggbbaa" or as a name from colornames. An empty string is
+// no color.
+func parseColor(s string) (color.Color, error) {
+	if s == "" {
+		return nil, nil
+	}
+	if c, ok := colornames.Map[strings.ToLower(s)]; ok {
+		return c, nil
+	}
+	hex, ok := strings.CutPrefix(s, "#")
+	if !ok || (len(hex) != 6 && len(hex) != 8) {
+		return nil, fmt.Errorf("invalid color %q", s)
+	}
+	if len(hex) == 6 {
+		hex += "ff"
+	}
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid color %q", s)
+	}
+	return color.NRGBA{R: uint8(v >> 24), G: uint8(v >> 16), B: uint8(v >> 8), A: uint8(v)}, nil
+}
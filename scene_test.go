@@ -0,0 +1,193 @@
+package pixelmunk
+
+import (
+	"bytes"
+	"github.com/vova616/chipmunk"
+	"github.com/vova616/chipmunk/vect"
+	"golang.org/x/image/colornames"
+	"image/color"
+	"io"
+	"strings"
+	"testing"
+)
+
+// sampleWorld creates a World with every kind of Object and joint that a scene can hold
+func sampleWorld(t *testing.T) *World {
+	t.Helper()
+	w := NewWorld("scene", 0, 0, 800, 600)
+	w.Space.Gravity = vect.Vect{Y: -900}
+	w.PhysicsHz = 120
+	w.Substeps = 2
+
+	floor := NewSegment(DrawableOptions{Color: colornames.Green, BodyOptions: BodyOptions{
+		StaticBody:     true,
+		Friction:       0.8,
+		SegmentOptions: SegmentOptions{A: vect.Vect{X: 0, Y: 20}, B: vect.Vect{X: 800, Y: 20}, Radius: 3},
+	}})
+	terrain, err := NewTerrain([]vect.Vect{{X: 0, Y: 0}, {X: 100, Y: 40}, {X: 200, Y: 10}}, DrawableOptions{BodyOptions: BodyOptions{
+		Position:       vect.Vect{X: 500, Y: 30},
+		Friction:       0.6,
+		SegmentOptions: SegmentOptions{Radius: 2},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	circle := NewCircle(DrawableOptions{Color: color.NRGBA{R: 0x12, G: 0x34, B: 0x56, A: 0x80}, BodyOptions: BodyOptions{
+		Position:      vect.Vect{X: 100, Y: 300},
+		Velocity:      vect.Vect{X: 50, Y: 10},
+		Mass:          2,
+		Elasticity:    0.5,
+		Friction:      0.4,
+		CollisionType: 3,
+		CircleOptions: CircleOptions{Radius: 15},
+	}})
+	box := NewBox(DrawableOptions{Thickness: 2, BodyOptions: BodyOptions{
+		Position:   vect.Vect{X: 200, Y: 300},
+		Angle:      0.3,
+		Mass:       3,
+		Friction:   0.7,
+		BoxOptions: BoxOptions{Width: 40, Height: 20},
+	}})
+	polygon, err := NewPolygon(DrawableOptions{BodyOptions: BodyOptions{
+		Position:       vect.Vect{X: 300, Y: 300},
+		Mass:           1,
+		PolygonOptions: PolygonOptions{Vertices: []vect.Vect{{X: -20, Y: -10}, {X: 20, Y: -10}, {X: 0, Y: 20}}},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	compound, err := NewCompound(DrawableOptions{BodyOptions: BodyOptions{Position: vect.Vect{X: 400, Y: 300}, Angle: -0.2}},
+		ShapeSpec{Type: chipmunk.ShapeType_Box, Mass: 2, Friction: 0.5, Color: colornames.Red, BoxOptions: BoxOptions{Width: 60, Height: 10}},
+		ShapeSpec{Type: chipmunk.ShapeType_Circle, Offset: vect.Vect{X: 30, Y: 10}, Mass: 1, CircleOptions: CircleOptions{Radius: 8}},
+		ShapeSpec{Type: chipmunk.ShapeType_Circle, Offset: vect.Vect{X: -30}, Sensor: true, CircleOptions: CircleOptions{Radius: 12}},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wheel := NewCircle(DrawableOptions{BodyOptions: BodyOptions{
+		Position:      vect.Vect{X: 600, Y: 300},
+		Mass:          1,
+		CircleOptions: CircleOptions{Radius: 10},
+	}})
+	// an anchor that isn't part of the World, only of a joint
+	anchor := NewCircle(DrawableOptions{BodyOptions: BodyOptions{
+		StaticBody:    true,
+		Position:      vect.Vect{X: 100, Y: 500},
+		CircleOptions: CircleOptions{Radius: 1},
+	}})
+	w.Add(floor, terrain, circle, box, polygon, compound, wheel)
+
+	jointOptions := DrawableOptions{Color: colornames.Yellow, JointOptions: JointOptions{Draw: true, BreakImpulse: 1e6}}
+	motor := NewMotor(compound, wheel, 2, jointOptions)
+	motor.GetConstraint().Constraint().MaxForce = 5000
+	w.Add(
+		NewPinJoint(anchor, circle, vect.Vect{}, vect.Vect{}, jointOptions),
+		NewJointWithAnchor(circle, box, vect.Vect{X: 15}, vect.Vect{X: -20}, jointOptions),
+		NewSlideJoint(box, polygon, vect.Vect{}, vect.Vect{}, 50, 120, jointOptions),
+		NewGrooveJoint(polygon, compound, vect.Vect{X: -10}, vect.Vect{X: 10}, vect.Vect{}, jointOptions),
+		NewSpring(compound, wheel, vect.Vect{X: 30}, vect.Vect{}, 150, 200, 5, DrawableOptions{JointOptions: JointOptions{Draw: true, Coils: 6}}),
+		NewRotarySpring(box, polygon, 0.5, 1000, 10, jointOptions),
+		NewRotaryLimit(circle, box, -1, 1, jointOptions),
+		NewRatchet(polygon, compound, 0, 0.4, jointOptions),
+		NewGear(box, compound, 0, 2, jointOptions),
+		motor,
+	)
+	return w
+}
+
+func TestLoadWorld_roundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		save func(w *World, out io.Writer) error
+	}{
+		{name: "json", save: (*World).Save},
+		{name: "yaml", save: (*World).SaveYAML},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := sampleWorld(t)
+			// move the World out of its initial state, so velocities are saved too
+			for range 10 {
+				w.Step(w.physicsDt())
+			}
+
+			var saved bytes.Buffer
+			if err := tt.save(w, &saved); err != nil {
+				t.Fatal(err)
+			}
+			loaded, err := LoadWorld(&saved)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// the loaded World saves the same scene as the original one
+			var want, got bytes.Buffer
+			if err = w.Save(&want); err != nil {
+				t.Fatal(err)
+			}
+			if err = loaded.Save(&got); err != nil {
+				t.Fatal(err)
+			}
+			if got.String() != want.String() {
+				t.Errorf("saved scene differs:\ngot:\n%s\nwant:\n%s", got.String(), want.String())
+			}
+			if len(loaded.Objects) != len(w.Objects) {
+				t.Fatalf("got %d Objects, want %d", len(loaded.Objects), len(w.Objects))
+			}
+			for i, object := range loaded.Objects {
+				if got, want := object.GetType(), w.Objects[i].GetType(); got != want {
+					t.Errorf("Object %d: got type %v, want %v", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadWorld_errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		scene   string
+		wantErr string
+	}{
+		{name: "version", scene: `{"version": 2}`, wantErr: "unsupported version 2"},
+		{name: "unknown field", scene: "version: 1\nworld: {name: test, speed: 2}", wantErr: "field speed not found"},
+		{name: "kind", scene: "version: 1\nobjects: [{id: 1, kind: star, body: {mass: 1}}]", wantErr: `unsupported kind "star"`},
+		{name: "color", scene: "version: 1\nobjects: [{id: 1, kind: circle, color: '#12345'}]", wantErr: `invalid color "#12345"`},
+		{
+			name:    "concave polygon in a compound",
+			scene:   "version: 1\nobjects: [{id: 1, kind: compound, body: {mass: 1}, shapes: [{type: polygon, vertices: [{x: 0, y: 0}, {x: 10, y: 0}, {x: 10, y: 10}, {x: 5, y: 2}, {x: 0, y: 10}]}]}]",
+			wantErr: "polygon: vertices are not convex",
+		},
+		{name: "circle without radius", scene: "version: 1\nobjects: [{id: 1, kind: circle, radius: 0, body: {mass: 1}}]", wantErr: "circle: radius must be positive"},
+		{name: "flat box", scene: "version: 1\nobjects: [{id: 1, kind: box, width: 10, body: {mass: 1}}]", wantErr: "box: width and height must be positive"},
+		{name: "segment without length", scene: "version: 1\nobjects: [{id: 1, kind: segment, a: {x: 1, y: 1}, b: {x: 1, y: 1}, body: {static: true}}]", wantErr: "segment: a and b must be different points"},
+		{name: "compound without shapes", scene: "version: 1\nobjects: [{id: 1, kind: compound, body: {mass: 1}}]", wantErr: "compound: a dynamic compound needs at least one shape"},
+		{name: "compound with an empty circle", scene: "version: 1\nobjects: [{id: 1, kind: compound, body: {mass: 1}, shapes: [{type: circle}]}]", wantErr: "circle: radius must be positive"},
+		{name: "terrain with a single point", scene: "version: 1\nobjects: [{id: 1, kind: terrain, points: [{x: 1, y: 2}]}]", wantErr: "terrain: need at least 2 points, got 1"},
+		{name: "no mass", scene: "version: 1\nobjects: [{id: 1, kind: circle, radius: 5}]", wantErr: "dynamic object without mass"},
+		{name: "duplicate id", scene: "version: 1\nobjects: [{id: 1, kind: circle, radius: 5, body: {mass: 1}}, {id: 1, kind: circle, radius: 5, body: {mass: 1}}]", wantErr: "duplicate object id 1"},
+		{name: "unknown object", scene: "version: 1\njoints: [{type: pin, a: 1, b: 2}]", wantErr: "unknown object"},
+		{name: "joint type", scene: "version: 1\nobjects: [{id: 1, kind: circle, radius: 5, body: {static: true}}]\njoints: [{type: weld, a: 1, b: 1}]", wantErr: `unsupported joint type "weld"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadWorld(strings.NewReader(tt.scene))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("got %v, want an error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWorld_Save_errors(t *testing.T) {
+	w := NewWorld("save", 0, 0, 100, 100)
+	circle := NewCircle(DrawableOptions{BodyOptions: BodyOptions{Mass: 1, CircleOptions: CircleOptions{Radius: 5}}})
+	// a dynamic body without shapes, outside the World, can't be loaded again
+	anchor := NewObject(chipmunk.NewBody(1, 1), DrawableOptions{})
+	w.Add(circle, NewPinJoint(anchor, circle, vect.Vect{}, vect.Vect{}, DrawableOptions{}))
+
+	var out bytes.Buffer
+	if err := w.Save(&out); err == nil || !strings.Contains(err.Error(), "dynamic body without shapes") {
+		t.Errorf("Save: got %v, want an error for the anchor", err)
+	}
+}
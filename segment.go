@@ -0,0 +1,18 @@
+package pixelmunk
+
+import (
+	"github.com/vova616/chipmunk"
+)
+
+// NewSegment creates a new Object for a line segment with rounded ends. Segments are typically used as static walls
+// and terrain: chipmunk doesn't detect collisions between two segments.
+func NewSegment(options DrawableOptions) *Object {
+	options.BodyOptions.Type = chipmunk.ShapeType_Segment
+	shape := chipmunk.NewSegment(
+		options.BodyOptions.SegmentOptions.A,
+		options.BodyOptions.SegmentOptions.B,
+		options.BodyOptions.SegmentOptions.Radius,
+	)
+
+	return NewObjectWithShape(shape, options)
+}
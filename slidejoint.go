@@ -0,0 +1,121 @@
+package pixelmunk
+
+import (
+	"github.com/gopxl/pixel/v2/ext/imdraw"
+	"github.com/vova616/chipmunk"
+	"github.com/vova616/chipmunk/vect"
+)
+
+// SlideJoint keeps the distance between the anchor points of two bodies between a minimum and a maximum,
+// as if they were connected by a rope (min = 0) or a telescopic rod
+type SlideJoint struct {
+	baseConstraint
+	slide *slideConstraint
+}
+
+var _ Drawable = &SlideJoint{}
+
+// NewSlideJoint creates a SlideJoint between two Objects. The anchors are relative to their Object.
+func NewSlideJoint(a, b *Object, anchorA, anchorB vect.Vect, min, max vect.Float, options DrawableOptions) *SlideJoint {
+	slide := newSlideConstraint(a.GetBody(), b.GetBody(), anchorA, anchorB, min, max)
+	return &SlideJoint{
+		baseConstraint: baseConstraint{constraint: slide, options: options},
+		slide:          slide,
+	}
+}
+
+// Draw draws the SlideJoint on the provided imdraw.IMDraw. The line between the anchors is solid when the joint
+// is at its minimum or maximum distance, and dashed when it is slack.
+func (j SlideJoint) Draw(imd *imdraw.IMDraw) {
+	j.drawPosed(imd, currentState)
+}
+
+// drawPosed draws the SlideJoint with its bodies at the position and angle returned by pose
+func (j SlideJoint) drawPosed(imd *imdraw.IMDraw, pose poseFunc) {
+	if j.options.JointOptions.Draw {
+		bodyA, bodyB := j.bodies()
+		poseA, poseB := pose(bodyA), pose(bodyB)
+		pA, pB := poseA.toWorld(j.slide.anchorA), poseB.toWorld(j.slide.anchorB)
+		anchorA, anchorB := toPixel(pA), toPixel(pB)
+
+		imd.Color = j.options.Color
+		if dist := vect.Dist(pA, pB); dist > j.slide.min && dist < j.slide.max {
+			drawDashedLine(imd, anchorA, anchorB, 5, j.options.Thickness)
+		} else {
+			drawLine(imd, anchorA, anchorB, j.options.Thickness)
+		}
+		drawAnchor(imd, anchorA)
+		drawAnchor(imd, anchorB)
+	}
+}
+
+type slideConstraint struct {
+	chipmunk.BasicConstraint
+	anchorA, anchorB vect.Vect
+	min, max         vect.Float
+
+	r1, r2, n    vect.Vect
+	nMass, bias  vect.Float
+	jnAcc, jnMax vect.Float
+}
+
+func newSlideConstraint(a, b *chipmunk.Body, anchorA, anchorB vect.Vect, min, max vect.Float) *slideConstraint {
+	return &slideConstraint{
+		BasicConstraint: chipmunk.NewConstraint(a, b),
+		anchorA:         anchorA,
+		anchorB:         anchorB,
+		min:             min,
+		max:             max,
+	}
+}
+
+func (c *slideConstraint) PreStep(dt vect.Float) {
+	a, b := c.BodyA, c.BodyB
+
+	c.r1 = rotate(a, c.anchorA)
+	c.r2 = rotate(b, c.anchorB)
+
+	delta := vect.Sub(vect.Add(b.Position(), c.r2), vect.Add(a.Position(), c.r1))
+	dist := vect.Length(delta)
+
+	var pdist vect.Float
+	switch {
+	case dist > c.max:
+		pdist = dist - c.max
+		c.n = normalize(delta, dist)
+	case dist < c.min:
+		pdist = c.min - dist
+		c.n = vect.Mult(normalize(delta, dist), -1)
+	default:
+		c.n = vect.Vect{}
+		c.jnAcc = 0
+	}
+
+	c.nMass = 1 / kScalar(a, b, c.r1, c.r2, c.n)
+	c.bias = clampFloat(-biasCoef(c.ErrorBias, dt)*pdist/dt, -c.MaxBias, c.MaxBias)
+	c.jnMax = c.MaxForce * dt
+}
+
+func (c *slideConstraint) ApplyCachedImpulse(dtCoef vect.Float) {
+	applyImpulses(c.BodyA, c.BodyB, c.r1, c.r2, vect.Mult(c.n, c.jnAcc*dtCoef))
+}
+
+func (c *slideConstraint) ApplyImpulse() {
+	if c.n == (vect.Vect{}) {
+		return
+	}
+	a, b := c.BodyA, c.BodyB
+
+	vrn := normalRelativeVelocity(a, b, c.r1, c.r2, c.n)
+	jn := (c.bias - vrn) * c.nMass
+	jnOld := c.jnAcc
+	// the joint can only pull the anchors back within range, never push them out of it
+	c.jnAcc = clampFloat(jnOld+jn, -c.jnMax, 0)
+	jn = c.jnAcc - jnOld
+
+	applyImpulses(a, b, c.r1, c.r2, vect.Mult(c.n, jn))
+}
+
+func (c *slideConstraint) Impulse() vect.Float {
+	return absFloat(c.jnAcc)
+}
@@ -0,0 +1,383 @@
+package pixelmunk
+
+import (
+	"github.com/vova616/chipmunk"
+	"github.com/vova616/chipmunk/vect"
+	"reflect"
+	"slices"
+)
+
+// Snapshot is the state of a World at one point in time: which Objects and joints are part of the World, the
+// position, angle, velocities and forces of every body, the accumulated impulses of every joint and the impulses that
+// chipmunk caches for the contacts between bodies. A Snapshot is a value: restoring it doesn't change it, so it can be
+// restored any number of times. A restored World follows the same path as the original one, except that contacts of
+// bodies that were removed since the Snapshot, or that ended since the Snapshot, start over without cached impulses.
+//
+// Only the physics is saved. Callbacks, key bindings and any state kept by the application (e.g. the timers in a
+// StepCallback) are left as they are.
+type Snapshot struct {
+	objects     []Drawable
+	bodies      []bodySnapshot
+	joints      []jointSnapshot
+	arbiters    []arbiterSnapshot
+	collisions  map[*chipmunk.Arbiter]collisionState
+	spaceBodies []*chipmunk.Body
+	constraints []chipmunk.Constraint
+	gravity     vect.Vect
+}
+
+type bodySnapshot struct {
+	drawable Drawable
+	body     *chipmunk.Body
+	// object is the Object of the body, nil if the Drawable isn't built on one
+	object  *Object
+	physics bodyPhysics
+	// chipmunk strips a removed body of its shapes and callbacks, so these are kept to revive it
+	static             bool
+	mass, moment       vect.Float
+	userData           any
+	callbackHandler    chipmunk.CollisionCallback
+	updatePositionFunc chipmunk.UpdatePositionFunction
+	updateVelocityFunc chipmunk.UpdateVelocityFunction
+	enabled            bool
+	ignoreGravity      bool
+	shapes             []chipmunk.Shape
+}
+
+// bodyPhysics is the part of a body that changes during a step
+type bodyPhysics struct {
+	position, velocity, force vect.Vect
+	angle, angularVelocity    vect.Float
+	torque                    vect.Float
+	vBias                     vect.Vect
+	wBias                     vect.Float
+}
+
+type jointSnapshot struct {
+	drawable     Drawable
+	bodyA, bodyB *chipmunk.Body
+	// restore copies the state of the solver back into the constraint. nil for unknown constraints.
+	restore func()
+}
+
+// arbiterSnapshot is a collision between two shapes, with the impulses of its contacts that chipmunk uses as
+// the starting point of the next step
+type arbiterSnapshot struct {
+	arb      *chipmunk.Arbiter
+	state    chipmunk.Arbiter
+	contacts []chipmunk.Contact
+}
+
+// Snapshot returns the current state of the World
+func (w *World) Snapshot() Snapshot {
+	s := Snapshot{
+		objects:     slices.Clone(w.Objects),
+		collisions:  make(map[*chipmunk.Arbiter]collisionState, len(w.collisions)),
+		spaceBodies: slices.Clone(w.Space.Bodies),
+		constraints: slices.Clone(w.Space.Constraints),
+		gravity:     w.Space.Gravity,
+	}
+	for _, object := range w.Objects {
+		switch object.GetType() {
+		case DrawableBody:
+			body := object.GetBody()
+			b := bodySnapshot{
+				drawable:           object,
+				body:               body,
+				object:             w.bodyObjects[body],
+				physics:            saveBodyPhysics(body),
+				static:             body.IsStatic(),
+				mass:               body.Mass(),
+				moment:             vect.Float(body.Moment()),
+				userData:           body.UserData,
+				callbackHandler:    body.CallbackHandler,
+				updatePositionFunc: body.UpdatePositionFunc,
+				updateVelocityFunc: body.UpdateVelocityFunc,
+				enabled:            body.Enabled,
+				ignoreGravity:      body.IgnoreGravity,
+			}
+			for _, shape := range body.Shapes {
+				b.shapes = append(b.shapes, *shape)
+			}
+			s.bodies = append(s.bodies, b)
+		case DrawableJoint:
+			constraint := object.GetConstraint()
+			basic := constraint.Constraint()
+			s.joints = append(s.joints, jointSnapshot{
+				drawable: object,
+				bodyA:    basic.BodyA,
+				bodyB:    basic.BodyB,
+				restore:  saveConstraint(constraint),
+			})
+		}
+	}
+	for _, arb := range w.Space.Arbiters {
+		a := arbiterSnapshot{arb: arb, state: *arb}
+		for _, contact := range arb.Contacts[:arb.NumContacts] {
+			a.contacts = append(a.contacts, *contact)
+		}
+		s.arbiters = append(s.arbiters, a)
+	}
+	for arb, state := range w.collisions {
+		s.collisions[arb] = *state
+	}
+	return s
+}
+
+// Restore returns the World to the state of the Snapshot. Objects and joints that were added since the Snapshot
+// are removed; those that were removed are added again. Restoring from a collision callback is done once the step
+// is complete.
+//
+// chipmunk doesn't allow a removed body back into the Space, so Objects that were removed since the Snapshot get a
+// new chipmunk.Body, which GetBody returns from then on. A removed body that isn't the body of an Object can't be
+// revived: joints attached to it are left out.
+func (w *World) Restore(s Snapshot) {
+	if w.stepping {
+		w.deferred = append(w.deferred, func() { w.Restore(s) })
+		return
+	}
+	w.releaseMouseDrag()
+
+	saved := make(map[Drawable]bool, len(s.objects))
+	for _, object := range s.objects {
+		saved[object] = true
+	}
+	var removed []Drawable
+	for _, object := range w.Objects {
+		if !saved[object] {
+			removed = append(removed, object)
+		}
+	}
+	w.Remove(removed...)
+
+	live := make(map[Drawable]bool, len(w.Objects))
+	for _, object := range w.Objects {
+		live[object] = true
+	}
+
+	// the body in the restored World of each body in the Snapshot: the current body of its Object, which is a new
+	// body if the Object was revived by an earlier Restore
+	current := make(map[*chipmunk.Body]*chipmunk.Body)
+	lost := make(map[*chipmunk.Body]bool)
+	for _, b := range s.bodies {
+		if live[b.drawable] {
+			body := b.drawable.GetBody()
+			b.physics.restore(body)
+			current[b.body] = body
+			continue
+		}
+		if body := b.revive(); body != nil {
+			current[b.body] = body
+			w.Add(b.drawable)
+		} else {
+			lost[b.body] = true
+		}
+	}
+	currentBody := func(body *chipmunk.Body) *chipmunk.Body {
+		if c, ok := current[body]; ok {
+			return c
+		}
+		return body
+	}
+
+	for _, j := range s.joints {
+		if lost[j.bodyA] || lost[j.bodyB] {
+			if live[j.drawable] {
+				w.Remove(j.drawable)
+			}
+			continue
+		}
+		basic := j.drawable.GetConstraint().Constraint()
+		if !live[j.drawable] {
+			basic.BodyA, basic.BodyB = currentBody(j.bodyA), currentBody(j.bodyB)
+			w.Add(j.drawable)
+		}
+		if j.restore != nil {
+			j.restore()
+		}
+		basic.BodyA, basic.BodyB = currentBody(j.bodyA), currentBody(j.bodyB)
+	}
+
+	// chipmunk's results depend on the order of the bodies and constraints, so restore that too
+	var spaceBodies []*chipmunk.Body
+	for _, body := range s.spaceBodies {
+		spaceBodies = append(spaceBodies, currentBody(body))
+	}
+	w.Space.Bodies = restoreOrder(w.Space.Bodies, spaceBodies)
+	w.Space.Constraints = restoreOrder(w.Space.Constraints, s.constraints)
+	w.Objects = restoreOrder(w.Objects, s.objects)
+	w.shapeKeyCache = nil
+	w.Space.Gravity = s.gravity
+	w.restoreContacts(s)
+}
+
+// restoreContacts restores the cached impulses of the contacts in the Snapshot. chipmunk only lets the arbiters of
+// the last step be changed, so a contact can only be restored if its shapes are still touching. The contacts of
+// the last step that aren't in the Snapshot lose their cached impulses.
+func (w *World) restoreContacts(s Snapshot) {
+	active := make(map[*chipmunk.Arbiter]bool, len(w.Space.Arbiters))
+	for _, arb := range w.Space.Arbiters {
+		active[arb] = true
+	}
+	restored := make(map[*chipmunk.Arbiter]bool, len(s.arbiters))
+	var arbiters []*chipmunk.Arbiter
+	for _, a := range s.arbiters {
+		arb := a.arb
+		if !active[arb] || !sameShapes(arb, a.state.ShapeA, a.state.ShapeB) {
+			continue
+		}
+		// the contacts of the Snapshot may have been reused for another arbiter: keep the current ones
+		contacts := arb.Contacts[:len(a.contacts)]
+		*arb = a.state
+		arb.Contacts = contacts
+		for i, contact := range a.contacts {
+			*contacts[i] = contact
+		}
+		restored[arb] = true
+		arbiters = append(arbiters, arb)
+	}
+	for _, arb := range w.Space.Arbiters {
+		if !restored[arb] {
+			arb.Contacts, arb.NumContacts = nil, 0
+		}
+	}
+	w.Space.Arbiters = arbiters
+
+	w.collisions = make(map[*chipmunk.Arbiter]*collisionState, len(s.collisions))
+	for arb, state := range s.collisions {
+		if sameShapes(arb, state.shapeA, state.shapeB) {
+			state := state
+			w.collisions[arb] = &state
+		}
+	}
+}
+
+// sameShapes reports whether the arbiter is between the two shapes, in either order
+func sameShapes(arb *chipmunk.Arbiter, a, b *chipmunk.Shape) bool {
+	return arb.ShapeA == a && arb.ShapeB == b || arb.ShapeA == b && arb.ShapeB == a
+}
+
+// saveBodyPhysics returns the current physics state of the body
+func saveBodyPhysics(body *chipmunk.Body) bodyPhysics {
+	return bodyPhysics{
+		position:        body.Position(),
+		velocity:        body.Velocity(),
+		force:           bodyForce(body),
+		angle:           body.Angle(),
+		angularVelocity: angularVelocity(body),
+		torque:          vect.Float(body.Torque()),
+		vBias:           body.VBias(),
+		wBias:           vect.Float(body.WBias()),
+	}
+}
+
+// restore gives the body the saved state. chipmunk doesn't put bodies to sleep, so the body is woken up, which
+// resets its idle time.
+func (s bodyPhysics) restore(body *chipmunk.Body) {
+	body.SetPosition(s.position)
+	body.SetAngle(s.angle)
+	body.SetVelocity(float32(s.velocity.X), float32(s.velocity.Y))
+	body.SetAngularVelocity(float32(s.angularVelocity))
+	body.SetForce(float32(s.force.X), float32(s.force.Y))
+	body.SetTorque(float32(s.torque))
+	body.SetVBias(s.vBias)
+	body.SetWBias(float32(s.wBias))
+	body.BodyActivate()
+	body.UpdateShapes()
+}
+
+// bodyForce returns the force applied to the body since the last step. chipmunk has no getter for it.
+func bodyForce(body *chipmunk.Body) vect.Vect {
+	f := reflect.ValueOf(body).Elem().FieldByName("f")
+	return vect.Vect{X: vect.Float(f.Field(0).Float()), Y: vect.Float(f.Field(1).Float())}
+}
+
+// revive gives the Object of a removed body a new body, with copies of the shapes, as it was at the time of the
+// Snapshot. It returns nil if the body doesn't belong to an Object.
+func (b bodySnapshot) revive() *chipmunk.Body {
+	if b.object == nil {
+		return nil
+	}
+	var body *chipmunk.Body
+	if b.static {
+		body = chipmunk.NewBodyStatic()
+	} else {
+		body = chipmunk.NewBody(b.mass, b.moment)
+	}
+	body.UserData = b.userData
+	body.CallbackHandler = b.callbackHandler
+	if h, ok := body.CallbackHandler.(*bodyCollisionHandler); ok {
+		// World.Add installs a new handler for the new body
+		body.CallbackHandler = h.next
+	}
+	body.UpdatePositionFunc = b.updatePositionFunc
+	body.UpdateVelocityFunc = b.updateVelocityFunc
+	body.Enabled = b.enabled
+	body.IgnoreGravity = b.ignoreGravity
+	for _, shape := range b.shapes {
+		body.AddShape(shape.Clone())
+	}
+	b.physics.restore(body)
+	b.object.body = body
+	return body
+}
+
+// restoreOrder returns the elements of current in the order in which they appear in saved. Elements that aren't
+// in saved are put at the end.
+func restoreOrder[T comparable](current, saved []T) []T {
+	remaining := make(map[T]bool, len(current))
+	for _, e := range current {
+		remaining[e] = true
+	}
+	ordered := make([]T, 0, len(current))
+	for _, e := range saved {
+		if remaining[e] {
+			ordered = append(ordered, e)
+			delete(remaining, e)
+		}
+	}
+	for _, e := range current {
+		if remaining[e] {
+			ordered = append(ordered, e)
+		}
+	}
+	return ordered
+}
+
+// saveConstraint returns a function that restores the constraint's current state, or nil if the type of
+// constraint is unknown
+func saveConstraint(constraint chipmunk.Constraint) func() {
+	switch c := constraint.(type) {
+	case *chipmunk.PivotJoint:
+		return saveState(c)
+	case *chipmunk.DampedSpring:
+		return saveState(c)
+	case *chipmunk.SimpleMotor:
+		return saveState(c)
+	case *pinConstraint:
+		return saveState(c)
+	case *slideConstraint:
+		return saveState(c)
+	case *grooveConstraint:
+		return saveState(c)
+	case *springConstraint:
+		return saveState(c)
+	case *rotarySpringConstraint:
+		return saveState(c)
+	case *rotaryLimitConstraint:
+		return saveState(c)
+	case *ratchetConstraint:
+		return saveState(c)
+	case *gearConstraint:
+		return saveState(c)
+	case *motorConstraint:
+		return saveState(c)
+	default:
+		return nil
+	}
+}
+
+func saveState[T any](v *T) func() {
+	saved := *v
+	return func() { *v = saved }
+}
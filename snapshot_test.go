@@ -0,0 +1,118 @@
+package pixelmunk
+
+import (
+	"github.com/vova616/chipmunk/vect"
+	"testing"
+)
+
+// stackWorld creates a World with a stack of boxes resting on a floor and a ball, attached to a static anchor by
+// a pin joint, that swings into the stack
+func stackWorld() (w *World, ball *Object, joint Drawable) {
+	w = NewWorld("stack", 0, 0, 400, 300)
+	w.Space.Gravity = vect.Vect{Y: -900}
+	w.Add(NewBox(DrawableOptions{BodyOptions: BodyOptions{
+		StaticBody: true,
+		Position:   vect.Vect{X: 200, Y: 5},
+		Friction:   0.8,
+		BoxOptions: BoxOptions{Width: 400, Height: 10},
+	}}))
+	for i := range 4 {
+		w.Add(NewBox(DrawableOptions{BodyOptions: BodyOptions{
+			Position:   vect.Vect{X: 200, Y: 25 + vect.Float(i)*30},
+			Mass:       1,
+			Friction:   0.8,
+			BoxOptions: BoxOptions{Width: 30, Height: 30},
+		}}))
+	}
+	anchor := NewCircle(DrawableOptions{BodyOptions: BodyOptions{
+		StaticBody:    true,
+		Position:      vect.Vect{X: 120, Y: 250},
+		CircleOptions: CircleOptions{Radius: 2},
+	}})
+	ball = NewCircle(DrawableOptions{BodyOptions: BodyOptions{
+		Position:      vect.Vect{X: 20, Y: 250},
+		Mass:          2,
+		Friction:      0.5,
+		CircleOptions: CircleOptions{Radius: 10},
+	}})
+	joint = NewPinJoint(anchor, ball, vect.Vect{}, vect.Vect{}, DrawableOptions{})
+	w.Add(anchor, ball, joint)
+	return w, ball, joint
+}
+
+func TestWorld_Restore(t *testing.T) {
+	tests := []struct {
+		name string
+		// change is called after each step of a run that is undone by restoring the Snapshot
+		change func(w *World, step int, ball *Object, joint Drawable)
+	}{
+		{name: "resting contacts"},
+		{
+			name: "removed ball",
+			change: func(w *World, step int, ball *Object, joint Drawable) {
+				if step == 10 {
+					w.Remove(joint, ball)
+				}
+			},
+		},
+		{
+			name: "added box",
+			change: func(w *World, step int, _ *Object, _ Drawable) {
+				if step == 10 {
+					w.Add(NewBox(DrawableOptions{BodyOptions: BodyOptions{
+						Position:   vect.Vect{X: 300, Y: 100},
+						Mass:       1,
+						BoxOptions: BoxOptions{Width: 20, Height: 20},
+					}}))
+				}
+			},
+		},
+		{
+			name: "pushed stack",
+			change: func(w *World, step int, _ *Object, _ Drawable) {
+				if step == 10 {
+					w.Objects[2].GetBody().SetVelocity(100, 50)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, ball, joint := stackWorld()
+			// let the stack come to rest
+			for range 120 {
+				w.Step(w.physicsDt())
+			}
+			if len(w.Space.Arbiters) == 0 {
+				t.Fatal("no resting contacts")
+			}
+			s := w.Snapshot()
+			const steps = 90
+			for range steps {
+				w.Step(w.physicsDt())
+			}
+			want := w.checksum()
+
+			// change the World, then restore the Snapshot twice: both runs must follow the first one
+			w.Restore(s)
+			for step := range steps {
+				w.Step(w.physicsDt())
+				if tt.change != nil {
+					tt.change(w, step, ball, joint)
+				}
+			}
+			for run := range 2 {
+				w.Restore(s)
+				if len(w.Objects) != len(s.objects) {
+					t.Errorf("run %d: got %d Objects, want %d", run, len(w.Objects), len(s.objects))
+				}
+				for range steps {
+					w.Step(w.physicsDt())
+				}
+				if got := w.checksum(); got != want {
+					t.Errorf("run %d: checksum %s, want %s", run, got, want)
+				}
+			}
+		})
+	}
+}
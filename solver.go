@@ -0,0 +1,112 @@
+package pixelmunk
+
+import (
+	"github.com/vova616/chipmunk"
+	"github.com/vova616/chipmunk/transform"
+	"github.com/vova616/chipmunk/vect"
+	"math"
+)
+
+// The constraint solvers in this package follow Chipmunk's own implementation. chipmunk.Body keeps its inverse mass,
+// velocity, etc. unexported, so the helpers below work through the Body's public methods instead.
+
+func invMass(body *chipmunk.Body) vect.Float {
+	return 1 / body.Mass()
+}
+
+func invMoment(body *chipmunk.Body) vect.Float {
+	return 1 / vect.Float(body.Moment())
+}
+
+func angularVelocity(body *chipmunk.Body) vect.Float {
+	return vect.Float(body.AngularVelocity())
+}
+
+// rotate rotates v by the angle of the body
+func rotate(body *chipmunk.Body, v vect.Vect) vect.Vect {
+	return transform.RotateVect(v, transform.NewRotation(body.Angle()))
+}
+
+// relativeVelocity returns the velocity of point r2 on body b, relative to point r1 on body a
+func relativeVelocity(a, b *chipmunk.Body, r1, r2 vect.Vect) vect.Vect {
+	v1 := vect.Add(b.Velocity(), vect.Mult(vect.Perp(r2), angularVelocity(b)))
+	v2 := vect.Add(a.Velocity(), vect.Mult(vect.Perp(r1), angularVelocity(a)))
+	return vect.Sub(v1, v2)
+}
+
+func normalRelativeVelocity(a, b *chipmunk.Body, r1, r2, n vect.Vect) vect.Float {
+	return vect.Dot(relativeVelocity(a, b, r1, r2), n)
+}
+
+// applyImpulse applies impulse j to the body, at offset r from its centre of gravity
+func applyImpulse(body *chipmunk.Body, r, j vect.Vect) {
+	v := vect.Mult(j, invMass(body))
+	body.AddVelocity(float32(v.X), float32(v.Y))
+	body.AddAngularVelocity(float32(invMoment(body) * vect.Cross(r, j)))
+}
+
+// applyImpulses applies impulse j to body b and the opposite impulse to body a
+func applyImpulses(a, b *chipmunk.Body, r1, r2, j vect.Vect) {
+	applyImpulse(a, r1, vect.Mult(j, -1))
+	applyImpulse(b, r2, j)
+}
+
+// applyAngularImpulses applies angular impulse j to body b and the opposite impulse to body a
+func applyAngularImpulses(a, b *chipmunk.Body, j vect.Float) {
+	a.AddAngularVelocity(float32(-j * invMoment(a)))
+	b.AddAngularVelocity(float32(j * invMoment(b)))
+}
+
+// kScalar returns the effective mass of the two bodies along n
+func kScalar(a, b *chipmunk.Body, r1, r2, n vect.Vect) vect.Float {
+	rcn1 := vect.Cross(r1, n)
+	rcn2 := vect.Cross(r2, n)
+	return invMass(a) + invMass(b) + invMoment(a)*rcn1*rcn1 + invMoment(b)*rcn2*rcn2
+}
+
+// kTensor returns the inverse of the effective mass matrix of the two bodies, as two column vectors
+func kTensor(a, b *chipmunk.Body, r1, r2 vect.Vect) (vect.Vect, vect.Vect) {
+	mSum := invMass(a) + invMass(b)
+
+	k11, k12, k21, k22 := mSum, vect.Float(0), vect.Float(0), mSum
+
+	aI := invMoment(a)
+	k11 += aI * r1.Y * r1.Y
+	k12 += -aI * r1.X * r1.Y
+	k21 += -aI * r1.X * r1.Y
+	k22 += aI * r1.X * r1.X
+
+	bI := invMoment(b)
+	k11 += bI * r2.Y * r2.Y
+	k12 += -bI * r2.X * r2.Y
+	k21 += -bI * r2.X * r2.Y
+	k22 += bI * r2.X * r2.X
+
+	detInv := 1 / (k11*k22 - k12*k21)
+	return vect.Vect{X: k22 * detInv, Y: -k12 * detInv}, vect.Vect{X: -k21 * detInv, Y: k11 * detInv}
+}
+
+func multK(v, k1, k2 vect.Vect) vect.Vect {
+	return vect.Vect{X: vect.Dot(v, k1), Y: vect.Dot(v, k2)}
+}
+
+// biasCoef returns the fraction of the error that the constraint corrects in one step of dt seconds
+func biasCoef(errorBias, dt vect.Float) vect.Float {
+	return 1 - vect.Float(math.Pow(float64(errorBias), float64(dt)))
+}
+
+func clampFloat(v, low, high vect.Float) vect.Float {
+	return max(low, min(v, high))
+}
+
+// normalize returns v divided by its length, or the zero vector if v is zero
+func normalize(v vect.Vect, length vect.Float) vect.Vect {
+	if length == 0 {
+		return vect.Vect{}
+	}
+	return vect.Mult(v, 1/length)
+}
+
+func absFloat(v vect.Float) vect.Float {
+	return vect.Float(math.Abs(float64(v)))
+}
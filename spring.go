@@ -0,0 +1,159 @@
+package pixelmunk
+
+import (
+	"github.com/gopxl/pixel/v2"
+	"github.com/gopxl/pixel/v2/ext/imdraw"
+	"github.com/vova616/chipmunk"
+	"github.com/vova616/chipmunk/vect"
+	"math"
+)
+
+// Spring connects the anchor points of two bodies with a damped spring
+type Spring struct {
+	baseConstraint
+	spring *springConstraint
+}
+
+var _ Drawable = &Spring{}
+
+// NewSpring creates a damped Spring between two Objects. The anchors are relative to their Object. The spring pushes
+// or pulls the anchors towards restLength, with a force proportional to stiffness. damping slows down the relative
+// movement of the anchors.
+func NewSpring(a, b *Object, anchorA, anchorB vect.Vect, restLength, stiffness, damping vect.Float, options DrawableOptions) *Spring {
+	spring := newSpringConstraint(a.GetBody(), b.GetBody(), anchorA, anchorB, restLength, stiffness, damping)
+	return &Spring{
+		baseConstraint: baseConstraint{constraint: spring, options: options},
+		spring:         spring,
+	}
+}
+
+const (
+	// defaultCoils is the number of coils drawn for a Spring, if JointOptions.Coils is not set
+	defaultCoils = 8
+	// coilWidth is the width of a Spring's coils at its rest length
+	coilWidth = 16.0
+)
+
+// Draw draws the Spring on the provided imdraw.IMDraw as a zig-zag coil between the two anchors. The length of
+// the coil's wire stays the same, so the coil widens as the spring is compressed and narrows as it is stretched.
+func (s Spring) Draw(imd *imdraw.IMDraw) {
+	s.drawPosed(imd, currentState)
+}
+
+// drawPosed draws the Spring with its bodies at the position and angle returned by pose
+func (s Spring) drawPosed(imd *imdraw.IMDraw, pose poseFunc) {
+	if s.options.JointOptions.Draw {
+		bodyA, bodyB := s.bodies()
+		poseA, poseB := pose(bodyA), pose(bodyB)
+		anchorA, anchorB := toPixel(poseA.toWorld(s.spring.anchorA)), toPixel(poseB.toWorld(s.spring.anchorB))
+
+		imd.Color = s.options.Color
+		imd.Push(s.coil(anchorA, anchorB)...)
+		imd.Line(max(s.options.Thickness, 1))
+		drawAnchor(imd, anchorA)
+		drawAnchor(imd, anchorB)
+	}
+}
+
+// coil returns the points of the zig-zag line between a and b
+func (s Spring) coil(a, b pixel.Vec) []pixel.Vec {
+	coils := s.options.JointOptions.Coils
+	if coils <= 0 {
+		coils = defaultCoils
+	}
+	halfCoils := 2 * coils
+
+	axis := b.Sub(a)
+	length := axis.Len()
+	if length == 0 {
+		return []pixel.Vec{a, b}
+	}
+
+	// each half coil is a piece of wire of fixed length, running from one side of the coil to the other
+	restPitch := float64(s.spring.restLength) / float64(halfCoils)
+	wire := math.Hypot(restPitch, coilWidth)
+	pitch := length / float64(halfCoils)
+	amplitude := math.Sqrt(math.Max(wire*wire-pitch*pitch, 0)) / 2
+	normal := axis.Normal().Unit().Scaled(amplitude)
+
+	points := make([]pixel.Vec, 0, halfCoils+2)
+	points = append(points, a)
+	for i := 0; i < halfCoils; i++ {
+		p := a.Add(axis.Scaled((float64(i) + 0.5) / float64(halfCoils)))
+		if i%2 == 0 {
+			p = p.Add(normal)
+		} else {
+			p = p.Sub(normal)
+		}
+		points = append(points, p)
+	}
+	return append(points, b)
+}
+
+// springConstraint is a damped spring. chipmunk.DampedSpring rotates the second anchor by the angle of the first body,
+// so it is reimplemented here.
+type springConstraint struct {
+	chipmunk.BasicConstraint
+	anchorA, anchorB vect.Vect
+	restLength       vect.Float
+	stiffness        vect.Float
+	damping          vect.Float
+
+	r1, r2, n vect.Vect
+	nMass     vect.Float
+	targetVRN vect.Float
+	vCoef     vect.Float
+	jAcc      vect.Float
+}
+
+func newSpringConstraint(a, b *chipmunk.Body, anchorA, anchorB vect.Vect, restLength, stiffness, damping vect.Float) *springConstraint {
+	return &springConstraint{
+		BasicConstraint: chipmunk.NewConstraint(a, b),
+		anchorA:         anchorA,
+		anchorB:         anchorB,
+		restLength:      restLength,
+		stiffness:       stiffness,
+		damping:         damping,
+	}
+}
+
+func (c *springConstraint) PreStep(dt vect.Float) {
+	a, b := c.BodyA, c.BodyB
+
+	c.r1 = rotate(a, c.anchorA)
+	c.r2 = rotate(b, c.anchorB)
+
+	delta := vect.Sub(vect.Add(b.Position(), c.r2), vect.Add(a.Position(), c.r1))
+	dist := vect.Length(delta)
+	c.n = normalize(delta, dist)
+
+	k := kScalar(a, b, c.r1, c.r2, c.n)
+	c.nMass = 1 / k
+
+	c.targetVRN = 0
+	c.vCoef = 1 - vect.Float(math.Exp(float64(-c.damping*dt*k)))
+
+	// the spring force is applied here; ApplyImpulse only handles the damping
+	jSpring := (c.restLength - dist) * c.stiffness * dt
+	c.jAcc = jSpring
+	applyImpulses(a, b, c.r1, c.r2, vect.Mult(c.n, jSpring))
+}
+
+func (c *springConstraint) ApplyCachedImpulse(_ vect.Float) {
+}
+
+func (c *springConstraint) ApplyImpulse() {
+	a, b := c.BodyA, c.BodyB
+
+	vrn := normalRelativeVelocity(a, b, c.r1, c.r2, c.n)
+	vDamp := (c.targetVRN - vrn) * c.vCoef
+	c.targetVRN = vrn + vDamp
+
+	jDamp := vDamp * c.nMass
+	c.jAcc += jDamp
+	applyImpulses(a, b, c.r1, c.r2, vect.Mult(c.n, jDamp))
+}
+
+func (c *springConstraint) Impulse() vect.Float {
+	return absFloat(c.jAcc)
+}
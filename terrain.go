@@ -0,0 +1,81 @@
+package pixelmunk
+
+import (
+	"fmt"
+	"github.com/gopxl/pixel/v2/ext/imdraw"
+	"github.com/vova616/chipmunk"
+	"github.com/vova616/chipmunk/vect"
+	"math"
+)
+
+// Terrain is a static Object made up of a chain of segments, drawn as a single continuous line
+type Terrain struct {
+	*Object
+	points []vect.Vect
+}
+
+var _ Drawable = &Terrain{}
+
+// NewTerrain creates a static Terrain that follows the polyline through the provided points. The points are relative
+// to the Position in the BodyOptions. The thickness of the terrain is set by BodyOptions.SegmentOptions.Radius.
+// It returns an error if there are fewer than 2 points.
+func NewTerrain(points []vect.Vect, options DrawableOptions) (*Terrain, error) {
+	if len(points) < 2 {
+		return nil, fmt.Errorf("terrain: need at least 2 points, got %d", len(points))
+	}
+	options.BodyOptions.StaticBody = true
+	options.BodyOptions.Type = chipmunk.ShapeType_Segment
+
+	body := chipmunk.NewBodyStatic()
+	for i := 0; i+1 < len(points); i++ {
+		shape := chipmunk.NewSegment(points[i], points[i+1], options.BodyOptions.SegmentOptions.Radius)
+		shape.SetElasticity(options.BodyOptions.Elasticity)
+		shape.SetFriction(options.BodyOptions.Friction)
+
+		// let chipmunk know how the segments are chained, so objects don't catch on the joins
+		segment := shape.GetAsSegment()
+		if i > 0 {
+			segment.A_tangent = vect.Sub(points[i-1], points[i])
+		}
+		if i+2 < len(points) {
+			segment.B_tangent = vect.Sub(points[i+2], points[i+1])
+		}
+		body.AddShape(shape)
+	}
+	body.SetPosition(options.BodyOptions.Position)
+	body.SetAngle(options.BodyOptions.Angle)
+
+	return &Terrain{
+		Object: NewObject(body, options),
+		points: points,
+	}, nil
+}
+
+// NewHeightmapTerrain creates a static Terrain from a heightmap: heights[i] is the height of the terrain
+// at x = i * spacing, relative to the Position in the BodyOptions. It returns an error if there are fewer than
+// 2 heights.
+func NewHeightmapTerrain(heights []vect.Float, spacing vect.Float, options DrawableOptions) (*Terrain, error) {
+	points := make([]vect.Vect, len(heights))
+	for i, height := range heights {
+		points[i] = vect.Vect{X: vect.Float(i) * spacing, Y: height}
+	}
+	return NewTerrain(points, options)
+}
+
+// Draw draws the Terrain on the provided imdraw.IMDraw
+func (t Terrain) Draw(imd *imdraw.IMDraw) {
+	body := t.GetBody()
+
+	endShape := imd.EndShape
+	imd.Color = t.options.Color
+	imd.EndShape = imdraw.RoundEndShape
+	for _, point := range t.points {
+		imd.Push(toPixel(toWorld(body, point)))
+	}
+	imd.Line(math.Max(2*float64(t.options.BodyOptions.SegmentOptions.Radius), 1))
+	imd.EndShape = endShape
+
+	for _, customDrawFunc := range t.options.CustomDrawFunc {
+		customDrawFunc(t.Object, imd)
+	}
+}
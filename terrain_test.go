@@ -0,0 +1,37 @@
+package pixelmunk
+
+import (
+	"github.com/vova616/chipmunk/vect"
+	"testing"
+)
+
+func TestNewTerrain(t *testing.T) {
+	tests := []struct {
+		name       string
+		points     []vect.Vect
+		wantShapes int
+		wantErr    bool
+	}{
+		{name: "no points", wantErr: true},
+		{name: "single point", points: []vect.Vect{{X: 1, Y: 2}}, wantErr: true},
+		{name: "single segment", points: []vect.Vect{{X: 0, Y: 0}, {X: 10, Y: 0}}, wantShapes: 1},
+		{name: "hill", points: []vect.Vect{{X: 0, Y: 0}, {X: 10, Y: 5}, {X: 20, Y: 0}}, wantShapes: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			terrain, err := NewTerrain(tt.points, DrawableOptions{})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewTerrain: got error %v, want error: %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if terrain != nil {
+					t.Error("NewTerrain returned a Terrain with an error")
+				}
+				return
+			}
+			if got := len(terrain.GetBody().Shapes); got != tt.wantShapes {
+				t.Errorf("shapes: got %d, want %d", got, tt.wantShapes)
+			}
+		})
+	}
+}
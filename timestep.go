@@ -0,0 +1,133 @@
+package pixelmunk
+
+import (
+	"github.com/gopxl/pixel/v2"
+	"github.com/gopxl/pixel/v2/ext/imdraw"
+	"github.com/vova616/chipmunk"
+	"github.com/vova616/chipmunk/transform"
+	"github.com/vova616/chipmunk/vect"
+	"time"
+)
+
+const defaultMaxCatchUp = 5
+
+// bodyState is the position and angle of a body after a step
+type bodyState struct {
+	position vect.Vect
+	angle    vect.Float
+}
+
+// poseFunc returns the position and angle at which a body is drawn
+type poseFunc func(body *chipmunk.Body) bodyState
+
+// posedDrawable is implemented by Drawables that are drawn from the position of other bodies, like joints, so
+// they can be drawn with those bodies at their interpolated position
+type posedDrawable interface {
+	drawPosed(imd *imdraw.IMDraw, pose poseFunc)
+}
+
+// currentState returns the current position and angle of the body
+func currentState(body *chipmunk.Body) bodyState {
+	return bodyState{position: body.Position(), angle: body.Angle()}
+}
+
+// toWorld converts a point, relative to a body in this state, into world coordinates
+func (s bodyState) toWorld(v vect.Vect) vect.Vect {
+	xf := transform.NewTransform(s.position, s.angle)
+	return xf.TransformVect(v)
+}
+
+// matrixFrom returns the matrix that moves a body, drawn in the from state, to this state
+func (s bodyState) matrixFrom(from bodyState) pixel.Matrix {
+	return pixel.IM.
+		Moved(toPixel(from.position).Scaled(-1)).
+		Rotated(pixel.ZV, float64(s.angle-from.angle)).
+		Moved(toPixel(s.position))
+}
+
+// physicsDt returns the duration of a single physics step
+func (w *World) physicsDt() vect.Float {
+	hz := w.PhysicsHz
+	if hz <= 0 {
+		hz = w.FrameRate
+	}
+	return 1 / vect.Float(hz)
+}
+
+// advance steps the World as many times as needed to catch up with the elapsed (real) time, scaled by TimeScale,
+// but no more than MaxCatchUp times. It returns how far the World is between the last step and the next one,
+// from 0 to 1. A paused World is only stepped if StepOnce was called. While rewinding, the World goes back one
+// recorded step at a time instead.
+func (w *World) advance(elapsed time.Duration) float64 {
+	dt := float64(w.physicsDt())
+	maxCatchUp := w.MaxCatchUp
+	if maxCatchUp <= 0 {
+		maxCatchUp = defaultMaxCatchUp
+	}
+
+	if w.paused && !w.rewinding {
+		w.accumulator = 0
+		if w.stepOnce {
+			w.stepOnce = false
+			w.recordHistory()
+			w.saveBodyStates()
+			w.Step(vect.Float(dt))
+		}
+		return 1
+	}
+
+	// if the simulation falls too far behind, drop the missed time: the simulation slows down rather than
+	// trying to catch up with ever more steps per frame
+	scale := w.timeScale()
+	w.accumulator = min(w.accumulator+elapsed.Seconds()*scale, float64(maxCatchUp)*max(scale, 1)*dt)
+	for w.accumulator >= dt {
+		if w.rewinding {
+			if !w.rewindStep() {
+				w.accumulator = 0
+				break
+			}
+		} else {
+			w.recordHistory()
+			w.saveBodyStates()
+			w.Step(vect.Float(dt))
+		}
+		w.accumulator -= dt
+	}
+	return w.accumulator / dt
+}
+
+// saveBodyStates records the position and angle of all bodies before a step, to interpolate from
+func (w *World) saveBodyStates() {
+	if w.previous == nil {
+		w.previous = make(map[*chipmunk.Body]bodyState)
+	}
+	clear(w.previous)
+	for _, object := range w.Objects {
+		if object.GetType() == DrawableBody {
+			body := object.GetBody()
+			w.previous[body] = currentState(body)
+		}
+	}
+}
+
+// interpolatedState returns the position and angle of the body between the previous step and the last step.
+// Bodies that were added during the last step are at their current position.
+func (w *World) interpolatedState(body *chipmunk.Body, alpha float64) bodyState {
+	current := currentState(body)
+	previous, ok := w.previous[body]
+	if !ok {
+		return current
+	}
+	a := vect.Float(alpha)
+	return bodyState{
+		position: vect.Add(previous.position, vect.Mult(vect.Sub(current.position, previous.position), a)),
+		angle:    previous.angle + (current.angle-previous.angle)*a,
+	}
+}
+
+// drawInterpolated draws all Objects at their interpolated position, without moving their bodies
+func (w *World) drawInterpolated(win pixel.Target, alpha float64) {
+	w.draw(win, func(body *chipmunk.Body) bodyState {
+		return w.interpolatedState(body, alpha)
+	})
+}
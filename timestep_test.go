@@ -0,0 +1,64 @@
+package pixelmunk
+
+import (
+	"github.com/gopxl/pixel/v2"
+	"github.com/vova616/chipmunk/vect"
+	"golang.org/x/image/colornames"
+	"image"
+	"math"
+	"testing"
+)
+
+func TestWorld_drawInterpolated(t *testing.T) {
+	w := NewWorld("interpolated", 0, 0, 100, 100)
+	box := NewBox(DrawableOptions{
+		Color: colornames.Red,
+		BodyOptions: BodyOptions{
+			Position:   vect.Vect{X: 30, Y: 50},
+			Mass:       1,
+			BoxOptions: BoxOptions{Width: 60, Height: 6},
+		},
+	})
+	w.Add(box)
+	// the box moves to the right and turns a quarter while stepping
+	w.saveBodyStates()
+	body := box.GetBody()
+	body.SetPosition(vect.Vect{X: 70, Y: 50})
+	body.SetAngle(math.Pi / 2)
+
+	tests := []struct {
+		name    string
+		alpha   float64
+		in, out []image.Point
+	}{
+		{name: "previous step", alpha: 0, in: []image.Point{{55, 50}, {5, 50}}, out: []image.Point{{30, 70}, {70, 70}}},
+		{name: "halfway", alpha: 0.5, in: []image.Point{{50, 50}, {65, 65}}, out: []image.Point{{75, 50}, {50, 70}}},
+		{name: "last step", alpha: 1, in: []image.Point{{70, 70}, {70, 25}}, out: []image.Point{{55, 50}, {90, 50}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			img := image.NewRGBA(image.Rect(0, 0, 100, 100))
+			w.drawInterpolated(&imageTarget{img: img, matrix: pixel.IM}, tt.alpha)
+
+			for _, p := range tt.in {
+				if !isRed(img, p) {
+					t.Errorf("%v: not drawn", p)
+				}
+			}
+			for _, p := range tt.out {
+				if isRed(img, p) {
+					t.Errorf("%v: drawn", p)
+				}
+			}
+			if body.Position() != (vect.Vect{X: 70, Y: 50}) || body.Angle() != math.Pi/2 {
+				t.Errorf("body moved to %v, %v", body.Position(), body.Angle())
+			}
+		})
+	}
+}
+
+// isRed reports whether the point, in world coordinates, is drawn red
+func isRed(img *image.RGBA, p image.Point) bool {
+	c := img.RGBAAt(p.X, img.Bounds().Dy()-1-p.Y)
+	return c.R > 0x80 && c.G == 0 && c.B == 0
+}
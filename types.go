@@ -19,5 +19,5 @@ type Drawable interface {
 	GetOptions() DrawableOptions
 	GetType() DrawableType
 	GetBody() *chipmunk.Body
-	GetJoint() *chipmunk.PivotJoint
+	GetConstraint() chipmunk.Constraint
 }
@@ -0,0 +1,107 @@
+package pixelmunk
+
+import (
+	"github.com/gopxl/pixel/v2"
+	"github.com/gopxl/pixel/v2/backends/opengl"
+	"github.com/gopxl/pixel/v2/ext/imdraw"
+	"golang.org/x/image/colornames"
+)
+
+// ScaleMode determines how the World's Bounds are scaled to fit the window
+type ScaleMode int
+
+const (
+	// ScaleLetterbox scales the Bounds as large as possible, keeping their aspect ratio. The rest of the window
+	// is black.
+	ScaleLetterbox ScaleMode = iota
+	// ScaleStretch scales the Bounds to cover the whole window, even if that distorts the World
+	ScaleStretch
+)
+
+// WindowOptions configures the window opened by Run and RunContext. The World's Bounds are always scaled to fit
+// the window, so the window can have any size, independent of the World.
+type WindowOptions struct {
+	// Width and Height of the window. Default to the size of the World's Bounds or, for a fullscreen window,
+	// the size of the monitor.
+	Width, Height float64
+	Resizable     bool
+	Fullscreen    bool
+	// Monitor to show the fullscreen window on. Defaults to the primary monitor.
+	Monitor     *opengl.Monitor
+	VSync       bool
+	Undecorated bool
+	Scale       ScaleMode
+}
+
+// windowConfig returns the configuration of the window to open
+func (w *World) windowConfig() opengl.WindowConfig {
+	options := w.Window
+	width, height := w.Bounds.W(), w.Bounds.H()
+
+	var monitor *opengl.Monitor
+	if options.Fullscreen {
+		monitor = options.Monitor
+		if monitor == nil {
+			monitor = opengl.PrimaryMonitor()
+		}
+		width, height = monitor.Size()
+	}
+	if options.Width > 0 && options.Height > 0 {
+		width, height = options.Width, options.Height
+	}
+
+	return opengl.WindowConfig{
+		Title:       w.Name,
+		Bounds:      pixel.R(0, 0, width, height),
+		Monitor:     monitor,
+		Resizable:   options.Resizable,
+		VSync:       options.VSync,
+		Undecorated: options.Undecorated,
+	}
+}
+
+// fitWindow scales the World's Bounds to the current size of the window. The default run loop calls this every
+// frame, so the World follows the window when it's resized.
+func (w *World) fitWindow(win *opengl.Window) {
+	w.window = win.Bounds()
+	win.SetMatrix(w.screenMatrix())
+}
+
+// screenMatrix returns the matrix that projects the World's Bounds onto the window
+func (w *World) screenMatrix() pixel.Matrix {
+	if w.window.Area() == 0 || w.Bounds.Area() == 0 {
+		return pixel.IM
+	}
+	scale := pixel.V(w.window.W()/w.Bounds.W(), w.window.H()/w.Bounds.H())
+	if w.Window.Scale == ScaleLetterbox {
+		s := min(scale.X, scale.Y)
+		scale = pixel.V(s, s)
+	}
+	return pixel.IM.
+		Moved(w.Bounds.Center().Scaled(-1)).
+		ScaledXY(pixel.ZV, scale).
+		Moved(w.window.Center())
+}
+
+// drawLetterbox covers the parts of the window outside the World's Bounds, so Objects outside the Bounds aren't
+// visible in the black bars
+func (w *World) drawLetterbox(target pixel.Target) {
+	m := w.screenMatrix()
+	visible := pixel.Rect{Min: m.Unproject(w.window.Min), Max: m.Unproject(w.window.Max)}
+	b := w.Bounds
+
+	imd := imdraw.New(nil)
+	imd.Color = colornames.Black
+	for _, bar := range []pixel.Rect{
+		pixel.R(visible.Min.X, visible.Min.Y, b.Min.X, visible.Max.Y),
+		pixel.R(b.Max.X, visible.Min.Y, visible.Max.X, visible.Max.Y),
+		pixel.R(b.Min.X, visible.Min.Y, b.Max.X, b.Min.Y),
+		pixel.R(b.Min.X, b.Max.Y, b.Max.X, visible.Max.Y),
+	} {
+		if bar.W() > 0 && bar.H() > 0 {
+			imd.Push(bar.Min, bar.Max)
+			imd.Rectangle(0)
+		}
+	}
+	imd.Draw(target)
+}
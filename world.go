@@ -1,6 +1,7 @@
 package pixelmunk
 
 import (
+	"context"
 	"fmt"
 	"github.com/gopxl/pixel/v2"
 	"github.com/gopxl/pixel/v2/backends/opengl"
@@ -13,13 +14,67 @@ import (
 
 // World represents the world that chipmunk will simulate
 type World struct {
-	Name        string
-	Bounds      pixel.Rect
-	FrameRate   int
-	Space       *chipmunk.Space
-	RunFunc     func(*opengl.Window)
-	RunCallback func(*opengl.Window)
-	Objects     []Drawable
+	Name      string
+	Bounds    pixel.Rect
+	FrameRate int
+	// PhysicsHz is the number of steps per second with which the default run loop advances the simulation,
+	// independent of the FrameRate. Defaults to FrameRate.
+	PhysicsHz int
+	// Substeps splits every step into smaller chipmunk steps, which makes joints stiffer and stops fast
+	// Objects from passing through each other. Defaults to 1.
+	Substeps int
+	// MaxCatchUp is the maximum number of steps per frame (multiplied by TimeScale when fast-forwarding). If
+	// rendering falls further behind, the simulation slows down instead. Defaults to 5.
+	MaxCatchUp int
+	// TimeScale runs the default run loop in slow motion (less than 1) or fast-forward (more than 1).
+	// Defaults to 1.
+	TimeScale float64
+	// RewindSeconds is how much of the simulation the default run loop keeps, so it can be run backwards with
+	// Rewind. Zero keeps no history.
+	RewindSeconds float64
+	// DisableDebugKeys turns off the default hotkeys: P pauses and resumes the simulation, N advances it by a single
+	// step, + and - double and halve the TimeScale and holding Backspace rewinds it (see RewindSeconds). Set it to
+	// free the keys for the World's own bindings.
+	DisableDebugKeys bool
+	Space            *chipmunk.Space
+	RunFunc          func(*opengl.Window)
+	RunCallback      func(*opengl.Window)
+	StepCallback     func(dt vect.Float)
+	// OnJointBroken is called when a joint is removed from the World because its impulse exceeded
+	// its JointOptions.BreakImpulse
+	OnJointBroken func(joint Drawable, impulse vect.Float)
+	// OnClose is called when the simulation started by Run or RunContext ends
+	OnClose func()
+	// Window configures the window opened by Run and RunContext
+	Window WindowOptions
+	// Camera determines which part of the World is shown in the window. If nil, the window shows the World's Bounds.
+	Camera *Camera
+	// MouseDrag lets the user pick up Objects and throw them around with the mouse. Only supported by the default
+	// run loop.
+	MouseDrag MouseDragOptions
+	Objects   []Drawable
+
+	collisionCallbacks collisionCallbacks
+	collisions         map[*chipmunk.Arbiter]*collisionState
+	bodyObjects        map[*chipmunk.Body]*Object
+	removedBodies      map[*chipmunk.Body]bool
+	stepping           bool
+	deferred           []func()
+	drag               *mouseDrag
+	keyBindings        []keyBinding
+	scrollBindings     []func(*World, pixel.Vec)
+	mousePosition      vect.Vect
+	accumulator        float64
+	previous           map[*chipmunk.Body]bodyState
+	paused             bool
+	stepOnce           bool
+	history            snapshotRing
+	rewinding          bool
+	steps              int
+	replay             *replay
+	index              *sweepIndex
+	shapeKeyCache      map[*chipmunk.Shape]shapeKey
+	window             pixel.Rect
 }
 
 const defaultFrameRate = 60
@@ -35,48 +90,149 @@ func NewWorld(name string, minX, minY, maxX, maxY float64) *World {
 }
 
 // defaultRun is the default run function for a world. This is used if the run function isn't overridden by World.RunFunc
+//
+// The simulation is stepped at PhysicsHz, independently of the FrameRate: a slow frame is caught up with extra steps.
+// Bodies are drawn at their interpolated position between the last two steps, so motion stays smooth.
 func (w *World) defaultRun(win *opengl.Window) {
 	frameTicker := time.NewTicker(time.Second / time.Duration(w.FrameRate))
+	defer frameTicker.Stop()
 	timer := time.Now()
+	last := time.Now()
 
 	for !win.Closed() {
-		w.Space.Step(1.0 / vect.Float(w.FrameRate))
+		w.handleInput(win)
+		w.updateMouseDrag(win, 1.0/vect.Float(w.FrameRate))
+		now := time.Now()
+		alpha := w.advance(now.Sub(last))
+		w.updateCamera(now.Sub(last), alpha)
+		last = now
 
 		win.Clear(colornames.Black)
-		w.Draw(win)
+		w.fitWindow(win)
+		w.drawInterpolated(win, alpha)
+		w.drawLetterbox(win)
 		win.Update()
 
 		if w.RunCallback != nil {
 			w.RunCallback(win)
 		}
 
-		win.SetTitle(fmt.Sprintf("%s (%.1f fps)", w.Name, 1/time.Since(timer).Seconds()))
+		win.SetTitle(fmt.Sprintf("%s (%.1f fps)%s", w.Name, 1/time.Since(timer).Seconds(), w.status()))
 		timer = time.Now()
 
 		<-frameTicker.C
 	}
 }
 
-// Add adds a new Object to the World
+// Step advances the simulation by dt seconds and calls the StepCallback, if one is set. If Substeps is set,
+// the step is split into as many chipmunk steps, each followed by any Objects added or removed from collision
+// callbacks and by the breaking of joints. When replaying, the recorded input for the step is handled first.
+// Step doesn't need a window, so it can be used to drive the World from tests or other headless code.
+func (w *World) Step(dt vect.Float) {
+	w.replayInputs()
+	w.useSweepIndex()
+	substeps := max(w.Substeps, 1)
+	stepDt := dt / vect.Float(substeps)
+	for range substeps {
+		w.stepping = true
+		w.Space.Step(stepDt)
+		w.stepping = false
+		// chipmunk removes the shapes of removed bodies at the end of the first step after their removal
+		w.removedBodies = nil
+
+		// Objects added or removed by collision callbacks
+		deferred := w.deferred
+		w.deferred = nil
+		for _, f := range deferred {
+			f()
+		}
+
+		w.breakJoints()
+	}
+	if w.StepCallback != nil {
+		w.StepCallback(dt)
+	}
+	w.steps++
+	w.checkReplay()
+	w.endReplay()
+}
+
+// breakJoints removes all joints whose impulse during the last step exceeded their BreakImpulse
+func (w *World) breakJoints() {
+	type broken struct {
+		joint   Drawable
+		impulse vect.Float
+	}
+	var joints []broken
+	for _, object := range w.Objects {
+		if object.GetType() != DrawableJoint {
+			continue
+		}
+		limit := object.GetOptions().JointOptions.BreakImpulse
+		if impulse := object.GetConstraint().Impulse(); limit > 0 && impulse > limit {
+			joints = append(joints, broken{joint: object, impulse: impulse})
+		}
+	}
+	for _, b := range joints {
+		w.Remove(b.joint)
+		if w.OnJointBroken != nil {
+			w.OnJointBroken(b.joint, b.impulse)
+		}
+	}
+}
+
+// Add adds a new Object to the World. Objects added during a step (i.e. from a collision callback) are added
+// once the step is complete. Add installs a chipmunk CallbackHandler on every body that reports its collisions to
+// the World's collision callbacks. If the body already has a CallbackHandler, that is still called.
 func (w *World) Add(objects ...Drawable) {
+	if w.stepping {
+		w.deferred = append(w.deferred, func() { w.Add(objects...) })
+		return
+	}
+	w.shapeKeyCache = nil
 	for _, object := range objects {
 		w.Objects = append(w.Objects, object)
 		switch object.GetType() {
 		case DrawableBody:
-			w.Space.AddBody(object.GetBody())
+			body := object.GetBody()
+			if o := objectOf(object); o != nil {
+				if w.bodyObjects == nil {
+					w.bodyObjects = make(map[*chipmunk.Body]*Object)
+				}
+				w.bodyObjects[body] = o
+			}
+			if _, ok := body.CallbackHandler.(*bodyCollisionHandler); !ok {
+				body.CallbackHandler = &bodyCollisionHandler{world: w, body: body, next: body.CallbackHandler}
+			}
+			w.Space.AddBody(body)
 		case DrawableJoint:
-			w.Space.AddConstraint(object.GetJoint())
+			w.Space.AddConstraint(object.GetConstraint())
 		}
 	}
 }
 
-// Remove removes an Object from the World
+// Remove removes an Object from the World. Objects removed during a step (i.e. from a collision callback) are removed
+// once the step is complete.
 func (w *World) Remove(objects ...Drawable) {
+	if w.stepping {
+		w.deferred = append(w.deferred, func() { w.Remove(objects...) })
+		return
+	}
+	w.shapeKeyCache = nil
 	for _, object := range objects {
 		if object.GetType() == DrawableBody {
+			if w.removedBodies == nil {
+				w.removedBodies = make(map[*chipmunk.Body]bool)
+			}
+			w.removedBodies[object.GetBody()] = true
+			w.separateAll(object.GetBody())
+			if w.drag != nil && w.drag.object.GetBody() == object.GetBody() {
+				w.releaseMouseDrag()
+			}
 			w.Space.RemoveBody(object.GetBody())
+			delete(w.bodyObjects, object.GetBody())
 		} else {
-			w.Space.RemoveConstraint(object.GetJoint())
+			w.Space.RemoveConstraint(object.GetConstraint())
 		}
 		for index, o := range w.Objects {
 			if o == object {
@@ -86,12 +242,36 @@ func (w *World) Remove(objects ...Drawable) {
 	}
 }
 
-// Draw draws all Objects in the World
+// Draw draws all Objects in the World, as seen by the World's Camera
 func (w *World) Draw(win pixel.Target) {
+	w.draw(win, currentState)
+}
+
+// draw draws all Objects in the World with their bodies at the position and angle returned by pose. Bodies are
+// moved there by the matrix they're drawn with, so custom draw functions are drawn there too.
+func (w *World) draw(win pixel.Target, pose poseFunc) {
 	imd := imdraw.New(nil)
+	view := w.viewMatrix()
+	imd.SetMatrix(view)
 	for _, object := range w.Objects {
+		if o, ok := object.(posedDrawable); ok {
+			o.drawPosed(imd, pose)
+			continue
+		}
+		if object.GetType() == DrawableBody {
+			body := object.GetBody()
+			if current, posed := currentState(body), pose(body); posed != current {
+				imd.SetMatrix(posed.matrixFrom(current).Chained(view))
+				object.Draw(imd)
+				imd.SetMatrix(view)
+				continue
+			}
+		}
 		object.Draw(imd)
 	}
+	if w.drag != nil {
+		w.drag.draw(imd, w.MouseDrag.Color, pose)
+	}
 	imd.Draw(win)
 }
 
@@ -100,20 +280,58 @@ func (w *World) Draw(win pixel.Target) {
 //			w := NewWorld("test", 0, 0, 1024, 1080)
 //	     // add some objects
 //			opengl.Run(w.Run)
+//
+// Run panics if the window can't be created. Use RunContext to handle the error, or to stop the simulation
+// from another goroutine.
 func (w *World) Run() {
-	cfg := opengl.WindowConfig{
-		Title:  w.Name,
-		Bounds: w.Bounds,
+	if err := w.RunContext(context.Background()); err != nil {
+		panic(err)
+	}
+}
+
+// RunContext runs the world simulation until the window is closed or the context is cancelled. It returns the
+// context's error if the context was cancelled. Like Run, it must be called from the function passed to opengl.Run:
+//
+//	opengl.Run(func() {
+//		if err := w.RunContext(ctx); err != nil && !errors.Is(err, context.Canceled) {
+//			log.Fatal(err)
+//		}
+//	})
+//
+// Cancelling the context closes the window, so a custom RunFunc stops as soon as it checks win.Closed().
+// OnClose is called before the window is destroyed.
+func (w *World) RunContext(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
 	}
 
-	win, err := opengl.NewWindow(cfg)
+	win, err := opengl.NewWindow(w.windowConfig())
 	if err != nil {
-		panic(err)
+		return fmt.Errorf("window: %w", err)
 	}
+	defer win.Destroy()
+	w.fitWindow(win)
+
+	closed := make(chan struct{})
+	stop := context.AfterFunc(ctx, func() {
+		defer close(closed)
+		win.SetClosed(true)
+	})
+	defer func() {
+		// if the context was cancelled, the window may still be being closed: wait for it before it's destroyed
+		if !stop() {
+			<-closed
+		}
+	}()
 
 	if w.RunFunc != nil {
 		w.RunFunc(win)
 	} else {
 		w.defaultRun(win)
 	}
+
+	if w.OnClose != nil {
+		w.OnClose()
+	}
+	return ctx.Err()
 }